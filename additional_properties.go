@@ -0,0 +1,274 @@
+package vjson
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+type additionalPropertiesMode int
+
+const (
+	// additionalPropertiesUnset is the default mode. it allows additional properties unless a parent schema is strict.
+	additionalPropertiesUnset additionalPropertiesMode = iota
+	additionalPropertiesAllow
+	additionalPropertiesDeny
+	additionalPropertiesValidate
+)
+
+const additionalPropertiesRule = "additional_properties"
+
+// Strict makes the schema reject properties which are not declared in its fields.
+// it is applied recursively to sub-schemas of object fields which do not set their own additional properties mode.
+func (s Schema) Strict() Schema {
+	s.additionalProperties = additionalPropertiesDeny
+	s.additionalField = nil
+	s.copyNestedFields()
+	s.propagateAdditionalProperties()
+	return s
+}
+
+// AllowAdditionalProperties makes the schema accept properties which are not declared in its fields.
+// it is applied recursively to sub-schemas of object fields which do not set their own additional properties mode.
+func (s Schema) AllowAdditionalProperties() Schema {
+	s.additionalProperties = additionalPropertiesAllow
+	s.additionalField = nil
+	s.copyNestedFields()
+	s.propagateAdditionalProperties()
+	return s
+}
+
+// AdditionalProperties makes the schema validate properties which are not declared in its fields with the given field.
+// unlike Strict, it is not applied to sub-schemas, and it has no effect on a schema which is created by NewRootSchema.
+func (s Schema) AdditionalProperties(field Field) Schema {
+	s.additionalProperties = additionalPropertiesValidate
+	s.additionalField = field
+	return s
+}
+
+// propagateAdditionalProperties sets allow or deny mode of the schema on nested object fields which have no mode.
+// ref fields are not followed, since their definitions are nested in the schema which declares them.
+// nested fields are changed in place, so they are copied with copyFields first if they may be shared.
+func (s *Schema) propagateAdditionalProperties() {
+	if s.additionalProperties != additionalPropertiesAllow && s.additionalProperties != additionalPropertiesDeny {
+		return
+	}
+	for _, field := range s.Fields {
+		propagateAdditionalProperties(field, s.additionalProperties)
+	}
+	if s.root != nil {
+		propagateAdditionalProperties(s.root, s.additionalProperties)
+	}
+	for _, definition := range s.definitions {
+		propagateAdditionalProperties(definition, s.additionalProperties)
+	}
+}
+
+func propagateAdditionalProperties(field Field, mode additionalPropertiesMode) {
+	switch f := field.(type) {
+	case *ObjectField:
+		if f.schema.additionalProperties == additionalPropertiesUnset {
+			f.schema.additionalProperties = mode
+			f.schema.propagateAdditionalProperties()
+		}
+	case *ArrayField:
+		propagateAdditionalProperties(f.items, mode)
+	case *CombinatorField:
+		for _, branch := range f.fields {
+			propagateAdditionalProperties(branch, mode)
+		}
+	}
+}
+
+// copyNestedFields replaces the nested fields of the schema with copies, so Strict and AllowAdditionalProperties do
+// not change the schema which they are called on. ref fields are resolved to the copies of their definitions.
+func (s *Schema) copyNestedFields() {
+	copies := make(map[Field]Field)
+	s.copyFields(copies)
+	for _, copied := range copies {
+		if ref, ok := copied.(*RefField); ok && ref.field != nil {
+			if definition, found := copies[ref.field]; found {
+				ref.field = definition
+			}
+		}
+	}
+}
+
+// copyFields replaces the fields of the schema, its definitions and its conditionals with copies.
+// copies holds the copy of every copied field, so a field which is used twice is copied once.
+func (s *Schema) copyFields(copies map[Field]Field) {
+	if s.definitions != nil {
+		definitions := make(map[string]Field, len(s.definitions))
+		for name, definition := range s.definitions {
+			definitions[name] = copyField(definition, copies)
+		}
+		s.definitions = definitions
+	}
+	if s.Fields != nil {
+		fields := make([]Field, len(s.Fields))
+		for index, field := range s.Fields {
+			fields[index] = copyField(field, copies)
+		}
+		s.Fields = fields
+	}
+	s.root = copyField(s.root, copies)
+	s.additionalField = copyField(s.additionalField, copies)
+	if s.conditionals != nil {
+		conditionals := make([]*Conditional, len(s.conditionals))
+		for index, conditional := range s.conditionals {
+			copied := *conditional
+			copied.condition.copyFields(copies)
+			if conditional.then != nil {
+				then := *conditional.then
+				then.copyFields(copies)
+				copied.then = &then
+			}
+			if conditional.otherwise != nil {
+				otherwise := *conditional.otherwise
+				otherwise.copyFields(copies)
+				copied.otherwise = &otherwise
+			}
+			conditionals[index] = &copied
+		}
+		s.conditionals = conditionals
+	}
+}
+
+// copyField returns a copy of a field of a built-in type which has nested fields. other fields are not changed by
+// propagateAdditionalProperties, so they are returned as they are.
+func copyField(field Field, copies map[Field]Field) Field {
+	switch f := field.(type) {
+	case *ObjectField:
+		if copied, found := copies[f]; found {
+			return copied
+		}
+		copied := *f
+		copies[f] = &copied
+		copied.schema.copyFields(copies)
+		return &copied
+	case *ArrayField:
+		if copied, found := copies[f]; found {
+			return copied
+		}
+		copied := *f
+		copies[f] = &copied
+		copied.items = copyField(f.items, copies)
+		return &copied
+	case *CombinatorField:
+		if copied, found := copies[f]; found {
+			return copied
+		}
+		copied := *f
+		copies[f] = &copied
+		copied.fields = make([]Field, len(f.fields))
+		for index, branch := range f.fields {
+			copied.fields[index] = copyField(branch, copies)
+		}
+		return &copied
+	case *RefField:
+		if copied, found := copies[f]; found {
+			return copied
+		}
+		copied := *f
+		copies[f] = &copied
+		return &copied
+	}
+	return field
+}
+
+// additionalPropertiesSpec returns the value of additional_properties key of the schema spec.
+func (s *Schema) additionalPropertiesSpec() interface{} {
+	switch s.additionalProperties {
+	case additionalPropertiesAllow:
+		return true
+	case additionalPropertiesDeny:
+		return false
+	case additionalPropertiesValidate:
+		return s.additionalField
+	}
+	return nil
+}
+
+// setAdditionalPropertiesSpec parses the value of additional_properties key of a schema spec.
+func (s *Schema) setAdditionalPropertiesSpec(spec interface{}) error {
+	switch value := spec.(type) {
+	case nil:
+		s.additionalProperties = additionalPropertiesUnset
+	case bool:
+		if value {
+			s.additionalProperties = additionalPropertiesAllow
+		} else {
+			s.additionalProperties = additionalPropertiesDeny
+		}
+	case map[string]interface{}:
+		field, err := s.getField(value)
+		if err != nil {
+			return errors.Wrap(err, "could not get field of additional_properties")
+		}
+		s.additionalProperties = additionalPropertiesValidate
+		s.additionalField = field
+	default:
+		return errors.Errorf("invalid format for additional_properties key")
+	}
+	return nil
+}
+
+// validateAdditionalProperties checks properties of a json object which are not declared in schema fields.
+func (s *Schema) validateAdditionalProperties(json gjson.Result) error {
+	if !s.checksAdditionalProperties() || !json.IsObject() {
+		return nil
+	}
+
+	declared := s.declaredNames()
+	var result error
+	json.ForEach(func(key, value gjson.Result) bool {
+		name := key.String()
+		if _, found := declared[name]; found {
+			return true
+		}
+		err := s.validateAdditionalProperty(name, value.Value(), func(field Field) error {
+			return validateResult(field, value)
+		})
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		return true
+	})
+	return result
+}
+
+// checksAdditionalProperties returns whether properties which are not declared in schema fields are checked.
+func (s *Schema) checksAdditionalProperties() bool {
+	if s.additionalProperties == additionalPropertiesValidate {
+		return s.additionalField != nil
+	}
+	return s.additionalProperties == additionalPropertiesDeny
+}
+
+// declaredNames returns the names of the properties which are declared in schema fields and conditionals.
+func (s *Schema) declaredNames() map[string]struct{} {
+	if s.compiled != nil {
+		return s.compiled.declared
+	}
+	declared := make(map[string]struct{}, len(s.Fields))
+	for _, field := range s.Fields {
+		declared[field.GetName()] = struct{}{}
+	}
+	for _, name := range s.conditionalFieldNames() {
+		declared[name] = struct{}{}
+	}
+	return declared
+}
+
+// validateAdditionalProperty validates the value of a property which is not declared in schema fields. validate
+// validates the value with the field of additional properties, so a null is validated like a null of a declared field.
+func (s *Schema) validateAdditionalProperty(name string, value interface{}, validate func(field Field) error) error {
+	if s.additionalProperties == additionalPropertiesDeny {
+		return prefixPath(newValidationError(name, additionalPropertiesRule, false, value, "Field %s is not allowed", name), name)
+	}
+	err := validate(s.additionalField)
+	if err != nil {
+		return prefixPath(err, name)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchema_Strict(t *testing.T) {
+	schema := NewSchema(
+		String("name"),
+		Object("address", NewSchema(
+			String("city"),
+		)),
+		Array("items", Object("item", NewSchema(
+			Integer("id"),
+		))),
+		Object("meta", NewSchema(
+			String("source"),
+		).AllowAdditionalProperties()),
+	).Strict()
+
+	err := schema.ValidateString(`{"name": "foo", "address": {"city": "bar"}, "items": [{"id": 1}], "meta": {"extra": 1}}`)
+	assert.Nil(t, err)
+
+	errs := schema.ValidateDetailed([]byte(`{"nmae": "foo", "address": {"ctiy": "bar"}, "items": [{"id": 1}, {"di": 2}]}`))
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "/address/ctiy", errs[0].Path)
+	assert.Equal(t, "additional_properties", errs[0].Rule)
+	assert.Equal(t, "/items/1/di", errs[1].Path)
+	assert.Equal(t, "/nmae", errs[2].Path)
+}
+
+func TestSchema_StrictCopy(t *testing.T) {
+	base := NewSchema(
+		Object("address", NewSchema(
+			String("city"),
+		)),
+		Ref("billing", "address"),
+	).Define("address", Object("address", NewSchema(
+		String("city"),
+	)))
+	strict := base.Strict()
+
+	input := `{"address": {"city": "bar", "zip": "1"}, "billing": {"city": "bar", "zip": "1"}}`
+	assert.Nil(t, base.ValidateString(input))
+
+	errs := strict.ValidateDetailed([]byte(input))
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "/address/zip", errs[0].Path)
+	assert.Equal(t, "/billing/zip", errs[1].Path)
+	assert.Nil(t, base.ValidateString(input))
+}
+
+func TestSchema_AdditionalProperties(t *testing.T) {
+	schema := NewSchema(
+		String("name"),
+	).AdditionalProperties(Integer("extra").Positive())
+
+	err := schema.ValidateString(`{"name": "foo", "a": 1, "b": 2}`)
+	assert.Nil(t, err)
+
+	errs := schema.ValidateDetailed([]byte(`{"name": "foo", "a": 1, "b": -2}`))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/b", errs[0].Path)
+	assert.Equal(t, "positive", errs[0].Rule)
+
+	errs = schema.ValidateDetailed([]byte(`{"name": "foo", "a": null}`))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/a", errs[0].Path)
+	assert.Equal(t, "nullable", errs[0].Rule)
+
+	errs = toValidationErrors(schema.ValidateValue(map[string]interface{}{"name": "foo", "a": nil}))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/a", errs[0].Path)
+	assert.Equal(t, "nullable", errs[0].Rule)
+
+	schema = NewSchema().AdditionalProperties(Integer("extra").Nullable())
+	assert.Nil(t, schema.ValidateString(`{"a": null}`))
+	assert.Nil(t, schema.ValidateValue(map[string]interface{}{"a": nil}))
+}
+
+func TestSchema_AdditionalPropertiesParsing(t *testing.T) {
+	schema, err := ReadFromFile("test/strict.json")
+	assert.Nil(t, err)
+	assert.Equal(t, additionalPropertiesDeny, schema.additionalProperties)
+	assert.Equal(t, additionalPropertiesDeny, schema.Fields[1].(*ObjectField).schema.additionalProperties)
+	assert.Equal(t, additionalPropertiesValidate, schema.Fields[2].(*ObjectField).schema.additionalProperties)
+
+	err = schema.ValidateString(`{"name": "foo", "address": {"city": "bar"}, "labels": {"env": "prod"}}`)
+	assert.Nil(t, err)
+
+	errs := schema.ValidateDetailed([]byte(`{"name": "foo", "address": {"zip": "1"}, "labels": {"env": 1}}`))
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "/address/zip", errs[0].Path)
+	assert.Equal(t, "/labels/env", errs[1].Path)
+
+	_, err = ReadFromString(`{"additional_properties": "no", "fields": []}`)
+	assert.NotNil(t, err)
+}
+
+func TestSchema_AdditionalPropertiesMarshalJSON(t *testing.T) {
+	schema := NewSchema(
+		String("name"),
+	).AdditionalProperties(String("extra"))
+
+	schemaBytes, err := json.Marshal(schema)
+	assert.Nil(t, err)
+
+	var newSchema Schema
+	err = json.Unmarshal(schemaBytes, &newSchema)
+	assert.Nil(t, err)
+	assert.Equal(t, additionalPropertiesValidate, newSchema.additionalProperties)
+	assert.Equal(t, "extra", newSchema.additionalField.GetName())
+
+	schemaBytes, err = json.Marshal(NewSchema(String("name")).Strict())
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"fields":[{"name":"name","type":"string"}],"additional_properties":false}`, string(schemaBytes))
+}
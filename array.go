@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 )
 
 // ArrayField is the type for validating arrays in a JSON
 type ArrayField struct {
 	name     string
 	required bool
+	nullable bool
 	items    Field
 
 	minLength           int
@@ -17,6 +19,9 @@ type ArrayField struct {
 
 	maxLength           int
 	maxLengthValidation bool
+
+	validators   customValidators
+	defaultValue interface{}
 }
 
 // To Force Implementing Field interface by ArrayField
@@ -27,48 +32,128 @@ func (a *ArrayField) GetName() string {
 	return a.name
 }
 
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (a *ArrayField) Custom(name string, fn ValidatorFunc) *ArrayField {
+	a.validators = a.validators.add(name, fn)
+	return a
+}
+
+func (a *ArrayField) getValidators() customValidators {
+	return a.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (a *ArrayField) Default(value []interface{}) *ArrayField {
+	a.defaultValue = value
+	return a
+}
+
+func (a *ArrayField) getDefault() (interface{}, bool) {
+	return a.defaultValue, a.defaultValue != nil
+}
+
 // Validate is used for validating a value. it returns an error if the value is invalid.
 func (a *ArrayField) Validate(v interface{}) error {
+	err := a.validateValue(v)
+	if err != nil || v == nil {
+		return err
+	}
+	return a.validators.validate(a.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (a *ArrayField) validateValue(v interface{}) error {
 	if v == nil {
 		if !a.required {
 			return nil
 		}
-		return errors.Errorf("Value for %s field is required", a.name)
+		return requiredError(a.name)
 	}
 
 	values, ok := v.([]interface{})
 	if !ok {
-		return errors.Errorf("Value of %s should be array", a.name)
+		return newValidationError(a.name, typeRule, arrayType, v, "Value of %s should be array", a.name)
+	}
+
+	result := a.validateLength(len(values))
+
+	for index, value := range values {
+		err := validateGoValue(a.items, value)
+		if err != nil {
+			result = multierror.Append(result, prefixIndex(err, index))
+		}
+	}
+	return result
+}
+
+func (a *ArrayField) validateResult(value gjson.Result) error {
+	if !value.Exists() {
+		return a.Validate(nil)
+	}
+	if !value.IsArray() {
+		return newValidationError(a.name, typeRule, arrayType, value.Value(), "Value of %s should be array", a.name)
 	}
 
 	var result error
-	if a.minLengthValidation {
-		if len(values) < a.minLength {
-			result = multierror.Append(result, errors.Errorf("length of %s array should be at least %d", a.name, a.minLength))
+	length := 0
+	value.ForEach(func(_, item gjson.Result) bool {
+		err := validateResult(a.items, item)
+		if err != nil {
+			result = multierror.Append(result, prefixIndex(err, length))
 		}
+		length++
+		return true
+	})
+
+	err := a.validateLength(length)
+	if err != nil {
+		result = multierror.Append(err, result)
 	}
+	return result
+}
 
-	if a.maxLengthValidation {
-		if len(values) > a.maxLength {
-			result = multierror.Append(result, errors.Errorf("length of %s array should be at most %d", a.name, a.maxLength))
+func (a *ArrayField) validateLength(length int) error {
+	var result error
+	if a.minLengthValidation {
+		if length < a.minLength {
+			result = multierror.Append(result, newValidationError(a.name, "min_length", a.minLength, length, "length of %s array should be at least %d", a.name, a.minLength))
 		}
 	}
 
-	for _, value := range values {
-		err := a.items.Validate(value)
-		if err != nil {
-			result = multierror.Append(result, errors.Wrapf(err, "%v item is invalid in %s array", value, a.name))
+	if a.maxLengthValidation {
+		if length > a.maxLength {
+			result = multierror.Append(result, newValidationError(a.name, "max_length", a.maxLength, length, "length of %s array should be at most %d", a.name, a.maxLength))
 		}
 	}
 	return result
 }
 
+func (a *ArrayField) compile() error {
+	err := compileField(a.items)
+	if err != nil {
+		return errors.Wrapf(err, "items field of array field %s is invalid", a.name)
+	}
+	return nil
+}
+
 // Required is called to make a field required in a JSON
 func (a *ArrayField) Required() *ArrayField {
 	a.required = true
 	return a
 }
 
+// Nullable is called to accept null as a valid value of the field in a JSON
+func (a *ArrayField) Nullable() *ArrayField {
+	a.nullable = true
+	return a
+}
+
+func (a *ArrayField) isNullable() bool {
+	return a.nullable
+}
+
 // MinLength is called to set minimum length for an array field in a JSON
 func (a *ArrayField) MinLength(length int) *ArrayField {
 	a.minLength = length
@@ -90,17 +175,20 @@ func (a *ArrayField) MarshalJSON() ([]byte, error) {
 	}
 
 	items := make(map[string]interface{})
-	err = json.Unmarshal(itemsRaw, &items)
+	err = decodeSpec(itemsRaw, &items)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not unmarshal items field of array field: %s", a.name)
 	}
 	return json.Marshal(ArrayFieldSpec{
-		Name:      a.name,
-		Type:      arrayType,
-		Required:  a.required,
-		Items:     items,
-		MinLength: a.minLength,
-		MaxLength: a.maxLength,
+		Name:       a.name,
+		Type:       arrayType,
+		Required:   a.required,
+		Nullable:   a.nullable,
+		Items:      items,
+		MinLength:  a.minLength,
+		MaxLength:  a.maxLength,
+		Validators: a.validators.names(),
+		Default:    a.defaultValue,
 	})
 }
 
@@ -2,12 +2,15 @@ package vjson
 
 // ArrayFieldSpec is a type used for parsing an ArrayField
 type ArrayFieldSpec struct {
-	Name      string                 `mapstructure:"name" json:"name"`
-	Type      fieldType              `json:"type"`
-	Required  bool                   `mapstructure:"required" json:"required,omitempty"`
-	Items     map[string]interface{} `mapstructure:"items" json:"items,omitempty"`
-	MinLength int                    `mapstructure:"min_length" json:"minLength,omitempty"`
-	MaxLength int                    `mapstructure:"max_length" json:"maxLength,omitempty"`
+	Name       string                 `mapstructure:"name" json:"name"`
+	Type       fieldType              `json:"type"`
+	Required   bool                   `mapstructure:"required" json:"required,omitempty"`
+	Nullable   bool                   `mapstructure:"nullable" json:"nullable,omitempty"`
+	Items      map[string]interface{} `mapstructure:"items" json:"items,omitempty"`
+	MinLength  int                    `mapstructure:"min_length" json:"minLength,omitempty"`
+	MaxLength  int                    `mapstructure:"max_length" json:"maxLength,omitempty"`
+	Validators []string               `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{}            `mapstructure:"default" json:"default,omitempty"`
 }
 
 // NewArray receives an ArrayFieldSpec and returns and ArrayField
@@ -15,10 +18,13 @@ func NewArray(spec ArrayFieldSpec, itemField Field, minLengthValidation, maxLeng
 	return &ArrayField{
 		name:                spec.Name,
 		required:            spec.Required,
+		nullable:            spec.Nullable,
 		items:               itemField,
 		minLength:           spec.MinLength,
 		minLengthValidation: minLengthValidation,
 		maxLength:           spec.MaxLength,
 		maxLengthValidation: maxLengthValidation,
+		validators:          namedValidators(spec.Validators),
+		defaultValue:        spec.Default,
 	}
 }
@@ -1,16 +1,17 @@
 package vjson
 
-import (
-	"encoding/json"
-	"github.com/pkg/errors"
-)
+import "encoding/json"
 
 // BooleanField is the type for validating booleans in a JSON
 type BooleanField struct {
 	name            string
 	required        bool
+	nullable        bool
 	valueValidation bool
 	value           bool
+
+	validators   customValidators
+	defaultValue interface{}
 }
 
 // To Force Implementing Field interface by BooleanField
@@ -21,24 +22,55 @@ func (b *BooleanField) GetName() string {
 	return b.name
 }
 
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (b *BooleanField) Custom(name string, fn ValidatorFunc) *BooleanField {
+	b.validators = b.validators.add(name, fn)
+	return b
+}
+
+func (b *BooleanField) getValidators() customValidators {
+	return b.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (b *BooleanField) Default(value bool) *BooleanField {
+	b.defaultValue = value
+	return b
+}
+
+func (b *BooleanField) getDefault() (interface{}, bool) {
+	return b.defaultValue, b.defaultValue != nil
+}
+
 // Validate is used for validating a value. it returns an error if the value is invalid.
 func (b *BooleanField) Validate(v interface{}) error {
+	err := b.validateValue(v)
+	if err != nil || v == nil {
+		return err
+	}
+	return b.validators.validate(b.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (b *BooleanField) validateValue(v interface{}) error {
 	if v == nil {
 		if !b.required {
 			return nil
 		}
-		return errors.Errorf("Value for %s field is required", b.name)
+		return requiredError(b.name)
 	}
 
 	value, ok := v.(bool)
 
 	if !ok {
-		return errors.Errorf("Value for %s should be a boolean", b.name)
+		return newValidationError(b.name, typeRule, booleanType, v, "Value for %s should be a boolean", b.name)
 	}
 
 	if b.valueValidation {
 		if value != b.value {
-			return errors.Errorf("Value for %s should be a %v", b.name, b.value)
+			return newValidationError(b.name, "value", b.value, value, "Value for %s should be a %v", b.name, b.value)
 		}
 	}
 
@@ -51,6 +83,16 @@ func (b *BooleanField) Required() *BooleanField {
 	return b
 }
 
+// Nullable is called to accept null as a valid value of the field in a JSON
+func (b *BooleanField) Nullable() *BooleanField {
+	b.nullable = true
+	return b
+}
+
+func (b *BooleanField) isNullable() bool {
+	return b.nullable
+}
+
 // ShouldBe is called for setting a value for checking a boolean.
 func (b *BooleanField) ShouldBe(value bool) *BooleanField {
 	b.value = value
@@ -60,10 +102,13 @@ func (b *BooleanField) ShouldBe(value bool) *BooleanField {
 
 func (b *BooleanField) MarshalJSON() ([]byte, error) {
 	return json.Marshal(BooleanFieldSpec{
-		Name:     b.name,
-		Type:     booleanType,
-		Required: b.required,
-		Value:    b.value,
+		Name:       b.name,
+		Type:       booleanType,
+		Required:   b.required,
+		Nullable:   b.nullable,
+		Value:      b.value,
+		Validators: b.validators.names(),
+		Default:    b.defaultValue,
 	})
 }
 
@@ -2,10 +2,13 @@ package vjson
 
 // BooleanFieldSpec is a type used for parsing an BooleanField
 type BooleanFieldSpec struct {
-	Name     string    `mapstructure:"name" json:"name"`
-	Type     fieldType `json:"type"`
-	Required bool      `mapstructure:"required" json:"required,omitempty"`
-	Value    bool      `mapstructure:"value" json:"value,omitempty"`
+	Name       string      `mapstructure:"name" json:"name"`
+	Type       fieldType   `json:"type"`
+	Required   bool        `mapstructure:"required" json:"required,omitempty"`
+	Nullable   bool        `mapstructure:"nullable" json:"nullable,omitempty"`
+	Value      bool        `mapstructure:"value" json:"value,omitempty"`
+	Validators []string    `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{} `mapstructure:"default" json:"default,omitempty"`
 }
 
 // NewBoolean receives an BooleanFieldSpec and returns and BooleanField
@@ -13,7 +16,10 @@ func NewBoolean(spec BooleanFieldSpec, valueValidation bool) *BooleanField {
 	return &BooleanField{
 		name:            spec.Name,
 		required:        spec.Required,
+		nullable:        spec.Nullable,
 		valueValidation: valueValidation,
 		value:           spec.Value,
+		validators:      namedValidators(spec.Validators),
+		defaultValue:    spec.Default,
 	}
 }
@@ -0,0 +1,840 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/miladibra10/vjson"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"go/format"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// options configures the generated code.
+type options struct {
+	typeName    string
+	packageName string
+	fluent      bool
+}
+
+// generator writes Go types, and optionally fluent schema code, for a schema spec.
+type generator struct {
+	types     bytes.Buffer
+	typeNames map[string]bool
+	// definitions are the field specs of schema definitions, which are written as types named by their keys.
+	definitions map[string]map[string]interface{}
+	// imports are the packages which are used by the generated types.
+	imports map[string]bool
+}
+
+// generateFile reads a schema file and returns the formatted generated code for it. the file is read like
+// vjson.ReadFromFile, so YAML and TOML files and files which include other files with $ref are supported.
+func generateFile(schemaPath string, opts options) ([]byte, error) {
+	content, err := vjson.ReadSpecFromLoader(vjson.DirLoader(""), filepath.ToSlash(schemaPath))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read schema file")
+	}
+	return generate(content, opts)
+}
+
+// generate returns the formatted generated code for a schema spec in json format.
+func generate(content []byte, opts options) ([]byte, error) {
+	// parsing the schema with vjson makes sure the spec is valid before generating code for it.
+	_, err := vjson.ReadFromBytes(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid schema")
+	}
+
+	var spec vjson.SchemaSpec
+	err = decodeJSON(content, &spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse schema spec")
+	}
+
+	g := &generator{typeNames: make(map[string]bool), definitions: spec.Definitions, imports: make(map[string]bool)}
+	if spec.Root != nil {
+		err = g.writeRoot(opts.typeName, spec.Root)
+	} else {
+		err = g.writeStruct(opts.typeName, objectFields(spec))
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range definitionNames(spec.Definitions) {
+		err = g.writeRoot(goName(name), spec.Definitions[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not generate type of definition %s", name)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by vjson-gen. DO NOT EDIT.\n\npackage %s\n\n", opts.packageName)
+	if opts.fluent {
+		g.imports["github.com/miladibra10/vjson"] = true
+	}
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, strconv.Quote(path))
+	}
+	sort.Strings(imports)
+	if len(imports) == 1 {
+		fmt.Fprintf(&out, "import %s\n\n", imports[0])
+	} else if len(imports) > 1 {
+		fmt.Fprintf(&out, "import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t"))
+	}
+	out.Write(g.types.Bytes())
+
+	if opts.fluent {
+		schema, err := schemaCode(spec)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&out, "// %sSchema returns the vjson schema of %s.\nfunc %sSchema() vjson.Schema {\n\treturn %s\n}\n", opts.typeName, opts.typeName, opts.typeName, schema)
+	}
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not format generated code")
+	}
+	return code, nil
+}
+
+// writeStruct writes a struct type for the given field specs. nested object fields are written as separate types
+// which are named by the parent type name followed by the field name. fields whose json names are converted to the
+// same Go name are reported as an error.
+func (g *generator) writeStruct(typeName string, fieldSpecs []map[string]interface{}) error {
+	if g.typeNames[typeName] {
+		return errors.Errorf("type name %s is generated more than once", typeName)
+	}
+	g.typeNames[typeName] = true
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "type %s struct {\n", typeName)
+	var nested []func() error
+	fieldNames := make(map[string]string, len(fieldSpecs))
+	for _, fieldSpec := range fieldSpecs {
+		name, _ := fieldSpec["name"].(string)
+		fieldName := goName(name)
+		if other, found := fieldNames[fieldName]; found {
+			return errors.Errorf("fields %s and %s of %s have the same Go name %s", other, name, typeName, fieldName)
+		}
+		fieldNames[fieldName] = name
+		required, _ := fieldSpec["required"].(bool)
+		goType, nestedTypes, err := g.goType(typeName+fieldName, fieldSpec, !required)
+		if err != nil {
+			return errors.Wrapf(err, "could not generate type of field %s", name)
+		}
+		nested = append(nested, nestedTypes...)
+
+		tag := name
+		if !required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&body, "\t%s %s `json:%s`\n", fieldName, goType, strconv.Quote(tag))
+	}
+	body.WriteString("}\n\n")
+	g.types.Write(body.Bytes())
+
+	for _, writeNested := range nested {
+		if err := writeNested(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRoot writes the type of the root field of a schema, or of a definition. the type is a struct if the field is
+// an object, otherwise it is a named type, and its nested types are named by the type name followed by Item.
+func (g *generator) writeRoot(typeName string, rootSpec map[string]interface{}) error {
+	if rootType, _ := rootSpec["type"].(string); rootType == "object" {
+		schemaSpec, _ := rootSpec["schema"].(map[string]interface{})
+		spec, err := decodeSchemaSpec(schemaSpec)
+		if err != nil {
+			return err
+		}
+		return g.writeStruct(typeName, objectFields(spec))
+	}
+
+	if g.typeNames[typeName] {
+		return errors.Errorf("type name %s is generated more than once", typeName)
+	}
+	g.typeNames[typeName] = true
+	goType, nested, err := g.goType(typeName+"Item", rootSpec, false)
+	if err != nil {
+		return errors.Wrap(err, "could not generate type of root field")
+	}
+	fmt.Fprintf(&g.types, "type %s %s\n\n", typeName, goType)
+	for _, writeNested := range nested {
+		if err := writeNested(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goType returns the Go type of a field spec. object fields return a function which writes their struct type,
+// so nested types are written after the type which uses them. optional objects are pointers, so they can be omitted.
+// ref fields use the type of their definition, which is a pointer for optional objects too, so recursive
+// definitions are valid Go types.
+func (g *generator) goType(typeName string, fieldSpec map[string]interface{}, optional bool) (string, []func() error, error) {
+	fieldType, _ := fieldSpec["type"].(string)
+	nullable, _ := fieldSpec["nullable"].(bool)
+	pointer := func(goType string) string {
+		if nullable {
+			return "*" + goType
+		}
+		return goType
+	}
+
+	switch fieldType {
+	case "integer":
+		// big integers are pointers already, and json.Unmarshal decodes them exactly
+		if big, _ := fieldSpec["big"].(bool); big {
+			g.imports["math/big"] = true
+			return "*big.Int", nil, nil
+		}
+		return pointer("int"), nil, nil
+	case "float":
+		return pointer("float64"), nil, nil
+	case "decimal":
+		// json.Number keeps the text of decimals, and it accepts numeric strings too
+		g.imports["encoding/json"] = true
+		return pointer("json.Number"), nil, nil
+	case "string":
+		return pointer("string"), nil, nil
+	case "boolean":
+		return pointer("bool"), nil, nil
+	case "array":
+		items, _ := fieldSpec["items"].(map[string]interface{})
+		itemType, nested, err := g.goType(typeName, items, false)
+		if err != nil {
+			return "", nil, err
+		}
+		return "[]" + itemType, nested, nil
+	case "object":
+		schemaSpec, _ := fieldSpec["schema"].(map[string]interface{})
+		spec, err := decodeSchemaSpec(schemaSpec)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(spec.Fields) == 0 && spec.AdditionalProperties != nil {
+			if additional, ok := spec.AdditionalProperties.(map[string]interface{}); ok {
+				valueType, nested, err := g.goType(typeName+"Value", additional, false)
+				if err != nil {
+					return "", nil, err
+				}
+				return "map[string]" + valueType, nested, nil
+			}
+		}
+		writeNested := func() error {
+			return g.writeStruct(typeName, objectFields(spec))
+		}
+		if optional && !nullable {
+			return "*" + typeName, []func() error{writeNested}, nil
+		}
+		return pointer(typeName), []func() error{writeNested}, nil
+	case "ref":
+		ref, _ := fieldSpec["ref"].(string)
+		definitionType, _ := g.definitions[ref]["type"].(string)
+		if definitionType == "object" && optional && !nullable {
+			return "*" + goName(ref), nil, nil
+		}
+		return pointer(goName(ref)), nil, nil
+	}
+	// null and combinator fields can hold values of different types.
+	return "interface{}", nil, nil
+}
+
+// objectFields returns the field specs of a struct for a schema spec. fields which are only declared in then or else
+// schemas of conditions are optional fields of the struct, since they are not always present.
+func objectFields(spec vjson.SchemaSpec) []map[string]interface{} {
+	fields := append([]map[string]interface{}{}, spec.Fields...)
+	declared := make(map[string]bool)
+	for _, fieldSpec := range spec.Fields {
+		name, _ := fieldSpec["name"].(string)
+		declared[name] = true
+	}
+	for _, conditionSpec := range spec.Conditions {
+		for _, branchSpec := range []map[string]interface{}{conditionSpec.Then, conditionSpec.Else} {
+			branch, err := decodeSchemaSpec(branchSpec)
+			if err != nil {
+				continue
+			}
+			for _, fieldSpec := range branch.Fields {
+				name, _ := fieldSpec["name"].(string)
+				if declared[name] {
+					continue
+				}
+				declared[name] = true
+				optional := make(map[string]interface{}, len(fieldSpec))
+				for key, value := range fieldSpec {
+					optional[key] = value
+				}
+				delete(optional, "required")
+				fields = append(fields, optional)
+			}
+		}
+	}
+	return fields
+}
+
+// schemaCode returns the fluent vjson code which builds a schema of the given spec.
+func schemaCode(spec vjson.SchemaSpec) (string, error) {
+	if spec.Root != nil {
+		root, err := fieldCode(spec.Root)
+		if err != nil {
+			return "", err
+		}
+		code, err := definitionsCode("vjson.NewRootSchema("+root+")", spec.Definitions)
+		if err != nil {
+			return "", err
+		}
+		return rulesCode(code, spec)
+	}
+	fields := make([]string, 0, len(spec.Fields))
+	for _, fieldSpec := range spec.Fields {
+		field, err := fieldCode(fieldSpec)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, field)
+	}
+
+	code := "vjson.NewSchema("
+	if len(fields) > 0 {
+		code += "\n" + strings.Join(fields, ",\n") + ",\n"
+	}
+	code += ")"
+	code, err := definitionsCode(code, spec.Definitions)
+	if err != nil {
+		return "", err
+	}
+	code, err = rulesCode(code, spec)
+	if err != nil {
+		return "", err
+	}
+
+	switch additional := spec.AdditionalProperties.(type) {
+	case bool:
+		if additional {
+			code += ".AllowAdditionalProperties()"
+		} else {
+			code += ".Strict()"
+		}
+	case map[string]interface{}:
+		field, err := fieldCode(additional)
+		if err != nil {
+			return "", err
+		}
+		code += ".AdditionalProperties(" + field + ")"
+	}
+	return code, nil
+}
+
+// definitionsCode appends the fluent code of schema definitions to the code of a schema.
+func definitionsCode(code string, definitions map[string]map[string]interface{}) (string, error) {
+	for _, name := range definitionNames(definitions) {
+		definitionSpec := definitions[name]
+		if specName, _ := definitionSpec["name"].(string); specName == "" {
+			named := map[string]interface{}{"name": name}
+			for key, value := range definitionSpec {
+				if key != "name" {
+					named[key] = value
+				}
+			}
+			definitionSpec = named
+		}
+		field, err := fieldCode(definitionSpec)
+		if err != nil {
+			return "", errors.Wrapf(err, "could not generate code of definition %s", name)
+		}
+		code += fmt.Sprintf(".\nDefine(%q, %s)", name, field)
+	}
+	return code, nil
+}
+
+// rulesCode appends the fluent code of conditions and dependent required fields of a schema spec to the code of a schema.
+func rulesCode(code string, spec vjson.SchemaSpec) (string, error) {
+	for index, conditionSpec := range spec.Conditions {
+		keywords := []string{"If", "Then", "Else"}
+		var conditional string
+		for keywordIndex, branchSpec := range []map[string]interface{}{conditionSpec.If, conditionSpec.Then, conditionSpec.Else} {
+			if branchSpec == nil {
+				continue
+			}
+			branch, err := decodeSchemaSpec(branchSpec)
+			if err != nil {
+				return "", err
+			}
+			branchCode, err := schemaCode(branch)
+			if err != nil {
+				return "", errors.Wrapf(err, "could not generate code of condition %d", index)
+			}
+			if keywordIndex == 0 {
+				conditional = "vjson.If(" + branchCode + ")"
+			} else {
+				conditional += "." + keywords[keywordIndex] + "(" + branchCode + ")"
+			}
+		}
+		code += ".\nWhen(" + conditional + ")"
+	}
+
+	fields := make([]string, 0, len(spec.DependentRequired))
+	for field := range spec.DependentRequired {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		arguments := []string{strconv.Quote(field)}
+		for _, required := range spec.DependentRequired[field] {
+			arguments = append(arguments, strconv.Quote(required))
+		}
+		code += ".\nDependentRequired(" + strings.Join(arguments, ", ") + ")"
+	}
+
+	for index, comparisonSpec := range spec.Comparisons {
+		comparison, err := comparisonCode(comparisonSpec)
+		if err != nil {
+			return "", errors.Wrapf(err, "could not generate code of comparison %d", index)
+		}
+		code += ".\n" + comparison
+	}
+	return code, nil
+}
+
+// comparisonMethods are the names of the Schema methods which compare the values of two fields, by their operators.
+var comparisonMethods = map[vjson.ComparisonOperator]string{
+	vjson.LessThanOperator:       "LessThan",
+	vjson.LessOrEqualOperator:    "LessOrEqual",
+	vjson.GreaterThanOperator:    "GreaterThan",
+	vjson.GreaterOrEqualOperator: "GreaterOrEqual",
+	vjson.EqualOperator:          "Equal",
+	vjson.NotEqualOperator:       "NotEqual",
+}
+
+// comparisonCode returns the fluent code of a comparison, e.g. LessThan("start", "end").
+func comparisonCode(spec vjson.ComparisonSpec) (string, error) {
+	method, found := comparisonMethods[spec.Operator]
+	if !found {
+		return "", errors.Errorf("invalid comparison operator: %s", spec.Operator)
+	}
+	left, leftIsField := spec.Left.(string)
+	right, rightIsField := spec.Right.(string)
+	if leftIsField && rightIsField {
+		return method + "(" + strconv.Quote(left) + ", " + strconv.Quote(right) + ")", nil
+	}
+
+	leftCode, err := operandCode(spec.Left)
+	if err != nil {
+		return "", err
+	}
+	rightCode, err := operandCode(spec.Right)
+	if err != nil {
+		return "", err
+	}
+	return "Compare(" + leftCode + ", vjson." + method + "Operator, " + rightCode + ")", nil
+}
+
+// operandCode returns the fluent code of an operand spec, which is either a field name or an object with sum or
+// count key.
+func operandCode(spec interface{}) (string, error) {
+	switch operand := spec.(type) {
+	case string:
+		return "vjson.FieldValue(" + strconv.Quote(operand) + ")", nil
+	case map[string]interface{}:
+		if names, ok := operand["sum"].([]interface{}); ok {
+			arguments := make([]string, 0, len(names))
+			for _, name := range names {
+				arguments = append(arguments, strconv.Quote(fmt.Sprint(name)))
+			}
+			return "vjson.Sum(" + strings.Join(arguments, ", ") + ")", nil
+		}
+		if name, ok := operand["count"].(string); ok {
+			return "vjson.Count(" + strconv.Quote(name) + ")", nil
+		}
+	}
+	return "", errors.Errorf("invalid comparison operand: %v", spec)
+}
+
+func definitionNames(definitions map[string]map[string]interface{}) []string {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fieldCode returns the fluent vjson code which builds a field of the given spec.
+func fieldCode(fieldSpec map[string]interface{}) (string, error) {
+	fieldType, _ := fieldSpec["type"].(string)
+	var code string
+	switch fieldType {
+	case "integer":
+		var spec vjson.IntegerFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode integer field")
+		}
+		code = fmt.Sprintf("vjson.Integer(%q)", spec.Name)
+		if _, found := fieldSpec["min"]; found {
+			code += fmt.Sprintf(".Min(%d)", spec.Min)
+		}
+		if _, found := fieldSpec["max"]; found {
+			code += fmt.Sprintf(".Max(%d)", spec.Max)
+		}
+		if _, found := fieldSpec["positive"]; found {
+			code += signCode(spec.Positive)
+		}
+		for _, r := range spec.Ranges {
+			code += fmt.Sprintf(".Range(%d, %d)", r.Start, r.End)
+		}
+		if spec.MultipleOf != nil {
+			code += fmt.Sprintf(".MultipleOf(%d)", *spec.MultipleOf)
+		}
+		if spec.Big {
+			code += ".Big()"
+		}
+	case "float":
+		var spec vjson.FloatFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode float field")
+		}
+		code = fmt.Sprintf("vjson.Float(%q)", spec.Name)
+		if _, found := fieldSpec["min"]; found {
+			code += fmt.Sprintf(".Min(%s)", floatCode(spec.Min))
+		}
+		if _, found := fieldSpec["max"]; found {
+			code += fmt.Sprintf(".Max(%s)", floatCode(spec.Max))
+		}
+		if _, found := fieldSpec["positive"]; found {
+			code += signCode(spec.Positive)
+		}
+		for _, r := range spec.Ranges {
+			code += fmt.Sprintf(".Range(%s, %s)", floatCode(r.Start), floatCode(r.End))
+		}
+	case "decimal":
+		var spec vjson.DecimalFieldSpec
+		if err := decodeDecimalSpec(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode decimal field")
+		}
+		code = fmt.Sprintf("vjson.Decimal(%q)", spec.Name)
+		if spec.Precision != nil {
+			code += fmt.Sprintf(".Precision(%d)", *spec.Precision)
+		}
+		if spec.Scale != nil {
+			code += fmt.Sprintf(".Scale(%d)", *spec.Scale)
+		}
+		if _, found := fieldSpec["min"]; found {
+			code += fmt.Sprintf(".Min(%q)", spec.Min)
+		}
+		if _, found := fieldSpec["max"]; found {
+			code += fmt.Sprintf(".Max(%q)", spec.Max)
+		}
+		for _, r := range spec.Ranges {
+			code += fmt.Sprintf(".Range(%q, %q)", r.Start, r.End)
+		}
+		if spec.AllowStrings {
+			code += ".AllowStrings()"
+		}
+	case "string":
+		var spec vjson.StringFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode string field")
+		}
+		code = fmt.Sprintf("vjson.String(%q)", spec.Name)
+		if _, found := fieldSpec["min_length"]; found {
+			code += fmt.Sprintf(".MinLength(%d)", spec.MinLength)
+		}
+		if _, found := fieldSpec["max_length"]; found {
+			code += fmt.Sprintf(".MaxLength(%d)", spec.MaxLength)
+		}
+		if _, found := fieldSpec["format"]; found {
+			code += fmt.Sprintf(".Format(%s)", strconv.Quote(spec.Format))
+		}
+		if _, found := fieldSpec["choices"]; found {
+			choices := make([]string, 0, len(spec.Choices))
+			for _, choice := range spec.Choices {
+				choices = append(choices, strconv.Quote(choice))
+			}
+			code += fmt.Sprintf(".Choices(%s)", strings.Join(choices, ", "))
+		}
+	case "boolean":
+		var spec vjson.BooleanFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode boolean field")
+		}
+		code = fmt.Sprintf("vjson.Boolean(%q)", spec.Name)
+		if _, found := fieldSpec["value"]; found {
+			code += fmt.Sprintf(".ShouldBe(%t)", spec.Value)
+		}
+	case "null":
+		name, _ := fieldSpec["name"].(string)
+		return fmt.Sprintf("vjson.Null(%q)", name), nil
+	case "array":
+		var spec vjson.ArrayFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode array field")
+		}
+		items, err := fieldCode(spec.Items)
+		if err != nil {
+			return "", err
+		}
+		code = fmt.Sprintf("vjson.Array(%q, %s)", spec.Name, items)
+		if _, found := fieldSpec["min_length"]; found {
+			code += fmt.Sprintf(".MinLength(%d)", spec.MinLength)
+		}
+		if _, found := fieldSpec["max_length"]; found {
+			code += fmt.Sprintf(".MaxLength(%d)", spec.MaxLength)
+		}
+	case "object":
+		var spec vjson.ObjectFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode object field")
+		}
+		schemaSpec, err := decodeSchemaSpec(spec.Schema)
+		if err != nil {
+			return "", err
+		}
+		schema, err := schemaCode(schemaSpec)
+		if err != nil {
+			return "", err
+		}
+		code = fmt.Sprintf("vjson.Object(%q, %s)", spec.Name, schema)
+	case "ref":
+		var spec vjson.RefFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrap(err, "could not decode ref field")
+		}
+		code = fmt.Sprintf("vjson.Ref(%q, %q)", spec.Name, spec.Ref)
+	case "one_of", "any_of", "all_of", "not":
+		var spec vjson.CombinatorFieldSpec
+		if err := mapstructure.Decode(fieldSpec, &spec); err != nil {
+			return "", errors.Wrapf(err, "could not decode %s field", fieldType)
+		}
+		fieldSpecs := spec.Fields
+		if fieldType == "not" {
+			fieldSpecs = []map[string]interface{}{spec.Field}
+		}
+		fields := make([]string, 0, len(fieldSpecs))
+		for _, branchSpec := range fieldSpecs {
+			field, err := fieldCode(branchSpec)
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, field)
+		}
+		constructors := map[string]string{"one_of": "OneOf", "any_of": "AnyOf", "all_of": "AllOf", "not": "Not"}
+		code = fmt.Sprintf("vjson.%s(%q, %s)", constructors[fieldType], spec.Name, strings.Join(fields, ", "))
+		if spec.Required {
+			code += ".Required()"
+		}
+		defaultValue, err := defaultCode(fieldType, fieldSpec)
+		if err != nil {
+			return "", err
+		}
+		return code + validatorsCode(spec.Validators) + defaultValue, nil
+	default:
+		return "", errors.Errorf("field type %s is not supported", fieldType)
+	}
+
+	if required, _ := fieldSpec["required"].(bool); required {
+		code += ".Required()"
+	}
+	if nullable, _ := fieldSpec["nullable"].(bool); nullable {
+		code += ".Nullable()"
+	}
+	var validators []string
+	if err := mapstructure.Decode(fieldSpec["validators"], &validators); err != nil {
+		return "", errors.Wrap(err, "could not decode validators of field")
+	}
+	defaultValue, err := defaultCode(fieldType, fieldSpec)
+	if err != nil {
+		return "", err
+	}
+	return code + validatorsCode(validators) + defaultValue, nil
+}
+
+// defaultCode returns the fluent code of the default value of a field.
+func defaultCode(fieldType string, fieldSpec map[string]interface{}) (string, error) {
+	value, found := fieldSpec["default"]
+	if !found || value == nil {
+		return "", nil
+	}
+	var literal string
+	var ok bool
+	switch fieldType {
+	case "integer":
+		var number json.Number
+		number, ok = value.(json.Number)
+		if ok {
+			_, err := number.Int64()
+			ok = err == nil
+		}
+		literal = number.String()
+	case "float":
+		var number json.Number
+		number, ok = value.(json.Number)
+		if ok {
+			_, err := number.Float64()
+			ok = err == nil
+		}
+		literal = number.String()
+	case "decimal":
+		switch v := value.(type) {
+		case json.Number:
+			ok = true
+			literal = strconv.Quote(v.String())
+		case string:
+			ok = true
+			literal = strconv.Quote(v)
+		}
+	case "string":
+		var text string
+		text, ok = value.(string)
+		literal = strconv.Quote(text)
+	case "boolean":
+		var boolean bool
+		boolean, ok = value.(bool)
+		literal = strconv.FormatBool(boolean)
+	case "array":
+		_, ok = value.([]interface{})
+		literal = valueCode(value)
+	case "object":
+		_, ok = value.(map[string]interface{})
+		literal = valueCode(value)
+	default:
+		ok = true
+		literal = valueCode(value)
+	}
+	if !ok {
+		return "", errors.Errorf("default value %v of %s field is invalid", value, fieldType)
+	}
+	return fmt.Sprintf(".Default(%s)", literal), nil
+}
+
+// valueCode returns a Go literal of a json value, which is decoded to interface{}.
+func valueCode(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, valueCode(item))
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(items, ", "))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		members := make([]string, 0, len(keys))
+		for _, key := range keys {
+			members = append(members, fmt.Sprintf("%q: %s", key, valueCode(v[key])))
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(members, ", "))
+	}
+	return "nil"
+}
+
+// decodeDecimalSpec decodes a decimal field spec, whose bounds may be written as json numbers or strings.
+func decodeDecimalSpec(fieldSpec map[string]interface{}, spec *vjson.DecimalFieldSpec) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+			if number, ok := data.(float64); ok && to.Kind() == reflect.String {
+				return strconv.FormatFloat(number, 'f', -1, 64), nil
+			}
+			return data, nil
+		},
+		Result: spec,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(fieldSpec)
+}
+
+// validatorsCode returns the fluent code of custom validators, which use the functions registered with their names.
+func validatorsCode(validators []string) string {
+	var code string
+	for _, name := range validators {
+		code += fmt.Sprintf(".Custom(%q, nil)", name)
+	}
+	return code
+}
+
+// decodeSchemaSpec decodes schema spec of an object field. json is used instead of mapstructure,
+// since additional_properties key has only a json tag.
+func decodeSchemaSpec(schemaSpec map[string]interface{}) (vjson.SchemaSpec, error) {
+	var spec vjson.SchemaSpec
+	content, err := json.Marshal(schemaSpec)
+	if err != nil {
+		return spec, errors.Wrap(err, "could not marshal schema of object field")
+	}
+	err = decodeJSON(content, &spec)
+	if err != nil {
+		return spec, errors.Wrap(err, "could not unmarshal schema of object field")
+	}
+	return spec, nil
+}
+
+// decodeJSON decodes a json schema spec. numbers are decoded as json.Number, so decimal bounds and defaults keep
+// their exact text.
+func decodeJSON(content []byte, spec interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	return decoder.Decode(spec)
+}
+
+func signCode(positive bool) string {
+	if positive {
+		return ".Positive()"
+	}
+	return ".Negative()"
+}
+
+func floatCode(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// commonInitialisms are written in upper case in Go names, like golint suggests.
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName converts a json name like first_name or firstName to an exported Go identifier like FirstName.
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var result strings.Builder
+	for _, word := range words {
+		if commonInitialisms[strings.ToUpper(word)] {
+			result.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		result.WriteString(string(runes))
+	}
+	if result.Len() == 0 {
+		return "Field"
+	}
+	goName := result.String()
+	if unicode.IsDigit([]rune(goName)[0]) {
+		goName = "F" + goName
+	}
+	return goName
+}
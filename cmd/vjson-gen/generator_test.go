@@ -0,0 +1,271 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const userSchema = `{
+	"fields": [
+		{"name": "id", "type": "integer", "required": true, "min": 1},
+		{"name": "first_name", "type": "string", "min_length": 2, "choices": ["a", "b"]},
+		{"name": "score", "type": "float", "nullable": true, "ranges": [{"start": 0.5, "end": 10}]},
+		{"name": "address", "type": "object", "required": true, "schema": {
+			"fields": [{"name": "city", "type": "string", "required": true}],
+			"additional_properties": false
+		}},
+		{"name": "tags", "type": "array", "max_length": 3, "items": {"name": "tag", "type": "object", "schema": {
+			"fields": [{"name": "label", "type": "string"}]
+		}}},
+		{"name": "meta", "type": "object", "schema": {"fields": [], "additional_properties": {"name": "value", "type": "integer"}}},
+		{"name": "either", "type": "one_of", "fields": [{"name": "a", "type": "string"}, {"name": "b", "type": "boolean", "value": true}]}
+	]
+}`
+
+func TestGenerate(t *testing.T) {
+	t.Run("types", func(t *testing.T) {
+		code, err := generate([]byte(userSchema), options{typeName: "User", packageName: "models"})
+		assert.Nil(t, err)
+		assert.Equal(t, `// Code generated by vjson-gen. DO NOT EDIT.
+
+package models
+
+type User struct {
+	ID        int            `+"`json:\"id\"`"+`
+	FirstName string         `+"`json:\"first_name,omitempty\"`"+`
+	Score     *float64       `+"`json:\"score,omitempty\"`"+`
+	Address   UserAddress    `+"`json:\"address\"`"+`
+	Tags      []UserTags     `+"`json:\"tags,omitempty\"`"+`
+	Meta      map[string]int `+"`json:\"meta,omitempty\"`"+`
+	Either    interface{}    `+"`json:\"either,omitempty\"`"+`
+}
+
+type UserAddress struct {
+	City string `+"`json:\"city\"`"+`
+}
+
+type UserTags struct {
+	Label string `+"`json:\"label,omitempty\"`"+`
+}
+`, string(code))
+	})
+	t.Run("fluent", func(t *testing.T) {
+		code, err := generate([]byte(userSchema), options{typeName: "User", packageName: "models", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), `import "github.com/miladibra10/vjson"`)
+		assert.Contains(t, string(code), `// UserSchema returns the vjson schema of User.
+func UserSchema() vjson.Schema {
+	return vjson.NewSchema(
+		vjson.Integer("id").Min(1).Required(),
+		vjson.String("first_name").MinLength(2).Choices("a", "b"),
+		vjson.Float("score").Range(0.5, 10).Nullable(),
+		vjson.Object("address", vjson.NewSchema(
+			vjson.String("city").Required(),
+		).Strict()).Required(),
+		vjson.Array("tags", vjson.Object("tag", vjson.NewSchema(
+			vjson.String("label"),
+		))).MaxLength(3),
+		vjson.Object("meta", vjson.NewSchema().AdditionalProperties(vjson.Integer("value"))),
+		vjson.OneOf("either", vjson.String("a"), vjson.Boolean("b").ShouldBe(true)),
+	)
+}`)
+	})
+	t.Run("optional_object", func(t *testing.T) {
+		code, err := generate([]byte(`{"fields": [{"name": "inner", "type": "object", "schema": {"fields": []}}]}`), options{typeName: "Outer", packageName: "main"})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "Inner *OuterInner `json:\"inner,omitempty\"`")
+	})
+	t.Run("root", func(t *testing.T) {
+		code, err := generate([]byte(`{"root": {"type": "array", "items": {"name": "user", "type": "object", "schema": {"fields": [{"name": "id", "type": "integer", "required": true}]}}}}`), options{typeName: "Users", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "type Users []UsersItem\n")
+		assert.Contains(t, string(code), "type UsersItem struct {\n\tID int `json:\"id\"`\n}")
+		assert.Contains(t, string(code), `return vjson.NewRootSchema(vjson.Array("", vjson.Object("user", vjson.NewSchema(`)
+
+		code, err = generate([]byte(`{"root": {"name": "user", "type": "object", "schema": {"fields": [{"name": "id", "type": "integer"}]}}}`), options{typeName: "User", packageName: "main"})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "type User struct {")
+	})
+	t.Run("definitions", func(t *testing.T) {
+		code, err := generate([]byte(`{
+			"definitions": {
+				"address": {"type": "object", "schema": {"fields": [{"name": "city", "type": "string", "required": true}]}},
+				"comment": {"type": "object", "schema": {"fields": [
+					{"name": "text", "type": "string", "required": true},
+					{"name": "replies", "type": "array", "items": {"name": "reply", "type": "ref", "ref": "comment"}},
+					{"name": "parent", "type": "ref", "ref": "comment"}
+				]}}
+			},
+			"fields": [
+				{"name": "billing", "type": "ref", "ref": "address", "required": true},
+				{"name": "shipping", "type": "ref", "ref": "address", "nullable": true},
+				{"name": "comments", "type": "array", "items": {"name": "comment", "type": "ref", "ref": "comment"}}
+			]
+		}`), options{typeName: "Order", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "Billing  Address   `json:\"billing\"`")
+		assert.Contains(t, string(code), "Shipping *Address  `json:\"shipping,omitempty\"`")
+		assert.Contains(t, string(code), "Comments []Comment `json:\"comments,omitempty\"`")
+		assert.Contains(t, string(code), "type Address struct {")
+		assert.Contains(t, string(code), "Replies []Comment `json:\"replies,omitempty\"`")
+		assert.Contains(t, string(code), "Parent  *Comment  `json:\"parent,omitempty\"`")
+		assert.Contains(t, string(code), `vjson.Ref("billing", "address").Required(),`)
+		assert.Contains(t, string(code), `Define("comment", vjson.Object("comment", vjson.NewSchema(`)
+		assert.Contains(t, string(code), `vjson.Array("replies", vjson.Ref("reply", "comment")),`)
+	})
+	t.Run("conditions", func(t *testing.T) {
+		code, err := generate([]byte(`{
+			"fields": [{"name": "payment_method", "type": "string", "required": true}],
+			"conditions": [{
+				"if": {"fields": [{"name": "payment_method", "type": "string", "choices": ["card"], "required": true}]},
+				"then": {"fields": [{"name": "card_number", "type": "string", "required": true}]}
+			}],
+			"dependent_required": {"start_date": ["end_date"]}
+		}`), options{typeName: "Payment", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "CardNumber    string `json:\"card_number,omitempty\"`")
+		assert.Contains(t, string(code), `When(vjson.If(vjson.NewSchema(`)
+		assert.Contains(t, string(code), `DependentRequired("start_date", "end_date")`)
+	})
+	t.Run("comparisons", func(t *testing.T) {
+		code, err := generate([]byte(`{
+			"fields": [{"name": "start", "type": "string"}, {"name": "end", "type": "string"}],
+			"comparisons": [
+				{"left": "start", "operator": "less_than", "right": "end"},
+				{"left": {"sum": ["subtotal", "tax"]}, "operator": "equal", "right": "total"},
+				{"left": {"count": "items"}, "operator": "greater_or_equal", "right": "min_items"}
+			]
+		}`), options{typeName: "Period", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), `LessThan("start", "end")`)
+		assert.Contains(t, string(code), `Compare(vjson.Sum("subtotal", "tax"), vjson.EqualOperator, vjson.FieldValue("total"))`)
+		assert.Contains(t, string(code), `Compare(vjson.Count("items"), vjson.GreaterOrEqualOperator, vjson.FieldValue("min_items"))`)
+	})
+	t.Run("validators", func(t *testing.T) {
+		code, err := generate([]byte(`{"fields": [
+			{"name": "card", "type": "string", "required": true, "validators": ["luhn"]},
+			{"name": "id", "type": "one_of", "fields": [{"name": "id", "type": "string"}], "validators": ["even"]}
+		]}`), options{typeName: "Payment", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), `vjson.String("card").Required().Custom("luhn", nil)`)
+		assert.Contains(t, string(code), `vjson.OneOf("id", vjson.String("id")).Custom("even", nil)`)
+	})
+	t.Run("defaults", func(t *testing.T) {
+		code, err := generate([]byte(`{"fields": [
+			{"name": "page_size", "type": "integer", "default": 20},
+			{"name": "ratio", "type": "float", "default": 0.5},
+			{"name": "sort", "type": "string", "default": "asc"},
+			{"name": "active", "type": "boolean", "default": false},
+			{"name": "tags", "type": "array", "items": {"name": "tag", "type": "string"}, "default": ["a"]},
+			{"name": "settings", "type": "object", "schema": {"fields": []}, "default": {"theme": "dark", "size": 2}}
+		]}`), options{typeName: "Query", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), `vjson.Integer("page_size").Default(20),`)
+		assert.Contains(t, string(code), `vjson.Float("ratio").Default(0.5),`)
+		assert.Contains(t, string(code), `vjson.String("sort").Default("asc"),`)
+		assert.Contains(t, string(code), `vjson.Boolean("active").Default(false),`)
+		assert.Contains(t, string(code), `vjson.Array("tags", vjson.String("tag")).Default([]interface{}{"a"}),`)
+		assert.Contains(t, string(code), `.Default(map[string]interface{}{"size": 2, "theme": "dark"}),`)
+
+		_, err = generate([]byte(`{"fields": [{"name": "page_size", "type": "integer", "default": 1.5}]}`), options{typeName: "Query", packageName: "main", fluent: true})
+		assert.NotNil(t, err)
+	})
+	t.Run("big_integers", func(t *testing.T) {
+		code, err := generate([]byte(`{"fields": [
+			{"name": "id", "type": "integer", "required": true, "big": true, "min": 1},
+			{"name": "amount", "type": "integer", "multiple_of": 5}
+		]}`), options{typeName: "Payment", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "import (\n\t\"github.com/miladibra10/vjson\"\n\t\"math/big\"\n)")
+		assert.Contains(t, string(code), "ID     *big.Int `json:\"id\"`")
+		assert.Contains(t, string(code), `vjson.Integer("id").Min(1).Big().Required(),`)
+		assert.Contains(t, string(code), `vjson.Integer("amount").MultipleOf(5),`)
+	})
+	t.Run("decimals", func(t *testing.T) {
+		code, err := generate([]byte(`{"fields": [
+			{"name": "price", "type": "decimal", "required": true, "precision": 10, "scale": 2, "min": 0.01, "max": "99999999.99", "allow_strings": true},
+			{"name": "discount", "type": "decimal", "nullable": true, "ranges": [{"start": "0", "end": "0.5"}], "default": "0.00"}
+		]}`), options{typeName: "Price", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "import (\n\t\"encoding/json\"\n\t\"github.com/miladibra10/vjson\"\n)")
+		assert.Contains(t, string(code), "Price    json.Number  `json:\"price\"`")
+		assert.Contains(t, string(code), "Discount *json.Number `json:\"discount,omitempty\"`")
+		assert.Contains(t, string(code), `vjson.Decimal("price").Precision(10).Scale(2).Min("0.01").Max("99999999.99").AllowStrings().Required(),`)
+		assert.Contains(t, string(code), `vjson.Decimal("discount").Range("0", "0.5").Nullable().Default("0.00"),`)
+
+		code, err = generate([]byte(`{"fields": [
+			{"name": "total", "type": "decimal", "max": 99999999999999999.99, "default": 12345678901234567890.12}
+		]}`), options{typeName: "Total", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), `vjson.Decimal("total").Max("99999999999999999.99").Default("12345678901234567890.12"),`)
+	})
+	t.Run("invalid_schema", func(t *testing.T) {
+		_, err := generate([]byte(`{"fields": [{"name": "foo"}]}`), options{typeName: "Foo", packageName: "main"})
+		assert.NotNil(t, err)
+	})
+	t.Run("duplicate_type_name", func(t *testing.T) {
+		_, err := generate([]byte(`{"fields": [
+			{"name": "a_b", "type": "object", "schema": {"fields": []}},
+			{"name": "a.b", "type": "object", "schema": {"fields": []}}
+		]}`), options{typeName: "Foo", packageName: "main"})
+		assert.NotNil(t, err)
+	})
+	t.Run("duplicate_field_name", func(t *testing.T) {
+		_, err := generate([]byte(`{"fields": [
+			{"name": "first_name", "type": "string"},
+			{"name": "firstName", "type": "string"}
+		]}`), options{typeName: "Foo", packageName: "main"})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "fields first_name and firstName of Foo have the same Go name FirstName")
+
+		_, err = generate([]byte(`{"fields": [
+			{"name": "user", "type": "object", "schema": {"fields": [
+				{"name": "id", "type": "integer"},
+				{"name": "ID", "type": "integer"}
+			]}}
+		]}`), options{typeName: "Foo", packageName: "main"})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "fields id and ID of FooUser have the same Go name ID")
+	})
+}
+
+func TestGenerateFile(t *testing.T) {
+	code, err := generateFile("../../test/object.json", options{typeName: "Object", packageName: "main"})
+	assert.Nil(t, err)
+	assert.Contains(t, string(code), "type Object struct {")
+
+	_, err = generateFile("../../test/not_found.json", options{typeName: "Object", packageName: "main"})
+	assert.NotNil(t, err)
+
+	t.Run("yaml", func(t *testing.T) {
+		code, err := generateFile("../../test/person.yaml", options{typeName: "Person", packageName: "main"})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "type Person struct {")
+		assert.Contains(t, string(code), "type PersonAddress struct {")
+	})
+	t.Run("toml", func(t *testing.T) {
+		code, err := generateFile("../../test/person.toml", options{typeName: "Person", packageName: "main"})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "type Person struct {")
+	})
+	t.Run("include", func(t *testing.T) {
+		code, err := generateFile("../../test/include/order.json", options{typeName: "Order", packageName: "main", fluent: true})
+		assert.Nil(t, err)
+		assert.Contains(t, string(code), "type OrderBilling struct {")
+		assert.Contains(t, string(code), "type OrderBillingCountry struct {")
+		assert.Contains(t, string(code), "type Money float64")
+	})
+	t.Run("invalid_include", func(t *testing.T) {
+		_, err := generateFile("../../test/include/cycle.json", options{typeName: "Cycle", packageName: "main"})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "FirstName", goName("first_name"))
+	assert.Equal(t, "FirstName", goName("firstName"))
+	assert.Equal(t, "UserID", goName("user_id"))
+	assert.Equal(t, "URL", goName("url"))
+	assert.Equal(t, "F1st", goName("1st"))
+	assert.Equal(t, "Field", goName("$"))
+}
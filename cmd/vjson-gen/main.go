@@ -0,0 +1,63 @@
+// Command vjson-gen generates Go types from a vjson schema file.
+//
+// Usage:
+//
+//	vjson-gen [flags] schema.json
+//
+// The schema file is parsed like vjson.ReadFromFile, so it may be a json, YAML or TOML file which includes other files
+// with $ref. Every object becomes a struct with json tags, nested objects become named nested structs and array items
+// become slices. With -fluent flag, a function which builds the same
+// schema with vjson fluent API is generated too.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the root struct type (default is derived from schema file name)")
+	packageName := flag.String("package", "main", "package name of the generated file")
+	output := flag.String("out", "", "output file (default is stdout)")
+	fluent := flag.Bool("fluent", false, "also generate a function which builds the schema with vjson fluent API")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: vjson-gen [flags] schema.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	schemaPath := flag.Arg(0)
+
+	if *typeName == "" {
+		base := filepath.Base(schemaPath)
+		*typeName = goName(strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+
+	code, err := generateFile(schemaPath, options{
+		typeName:    *typeName,
+		packageName: *packageName,
+		fluent:      *fluent,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vjson-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(code)
+	} else {
+		err = ioutil.WriteFile(*output, code, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vjson-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
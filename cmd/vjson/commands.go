@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/miladibra10/vjson"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	exitValid   = 0
+	exitInvalid = 1
+	exitError   = 2
+)
+
+const stdinName = "-"
+
+const usage = `Usage:
+  vjson validate --schema schema.json [--output human|json] [--input json|yaml|toml|ndjson] [file ...]
+  vjson check-schema schema.json ...
+`
+
+// fileResult is the result of validating a file, which is written in json output.
+// errors of ndjson files are kept per line in Lines.
+type fileResult struct {
+	File   string                  `json:"file"`
+	Valid  bool                    `json:"valid"`
+	Errors []vjson.ValidationError `json:"errors,omitempty"`
+	Lines  []vjson.StreamError     `json:"lines,omitempty"`
+}
+
+// run executes the command with the given arguments and returns its exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdin, stdout, stderr)
+	case "check-schema":
+		return runCheckSchema(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitValid
+	}
+	fmt.Fprintf(stderr, "vjson: unknown command %q\n%s", args[0], usage)
+	return exitError
+}
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	schemaPath := flags.String("schema", "", "path of the schema file (required)")
+	output := flags.String("output", "human", "output format of errors, human or json")
+	inputFormat := flags.String("input", "", "format of input files, json, yaml, toml or ndjson (default is detected by file extension)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *schemaPath == "" {
+		fmt.Fprintln(stderr, "vjson: --schema flag is required")
+		return exitError
+	}
+	if *output != "human" && *output != "json" {
+		fmt.Fprintf(stderr, "vjson: invalid output format %q\n", *output)
+		return exitError
+	}
+	if *inputFormat != "" && *inputFormat != "json" && *inputFormat != "yaml" && *inputFormat != "toml" && *inputFormat != "ndjson" {
+		fmt.Fprintf(stderr, "vjson: invalid input format %q\n", *inputFormat)
+		return exitError
+	}
+
+	validator, err := readValidator(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "vjson: %s: %v\n", *schemaPath, err)
+		return exitError
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{stdinName}
+	}
+
+	exitCode := exitValid
+	results := make([]fileResult, 0, len(files))
+	for _, file := range files {
+		var result fileResult
+		if format := fileFormat(file, *inputFormat); format == "ndjson" {
+			result, err = validateStream(validator, file, stdin)
+		} else {
+			result, err = validateFile(validator, file, stdin, format)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "vjson: %s: %v\n", file, err)
+			exitCode = exitError
+			continue
+		}
+		if !result.Valid && exitCode == exitValid {
+			exitCode = exitInvalid
+		}
+		results = append(results, result)
+
+		if *output == "human" {
+			writeHuman(stdout, result)
+		}
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			fmt.Fprintf(stderr, "vjson: could not write output: %v\n", err)
+			return exitError
+		}
+	}
+	return exitCode
+}
+
+func runCheckSchema(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+	exitCode := exitValid
+	for _, schemaPath := range args {
+		_, err := readValidator(schemaPath)
+		if err != nil {
+			for _, message := range errorMessages(err) {
+				fmt.Fprintf(stdout, "%s: %s\n", schemaPath, message)
+			}
+			exitCode = exitInvalid
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: ok\n", schemaPath)
+	}
+	return exitCode
+}
+
+// fileFormat returns the format of an input file, which is detected by its extension if it is not given.
+func fileFormat(file, format string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	}
+	return "json"
+}
+
+// validateFile validates a json, YAML or TOML document which is read as a whole.
+func validateFile(validator *vjson.Validator, file string, stdin io.Reader, format string) (fileResult, error) {
+	input, err := readInput(file, stdin)
+	if err != nil {
+		return fileResult{}, err
+	}
+	result := fileResult{File: file, Errors: validateInput(validator, input, format)}
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+func validateInput(validator *vjson.Validator, input []byte, format string) []vjson.ValidationError {
+	var err error
+	switch format {
+	case "yaml":
+		err = validator.ValidateYAML(input)
+	case "toml":
+		err = validator.ValidateTOML(input)
+	default:
+		return validator.ValidateDetailed(input)
+	}
+	return vjson.ValidationErrors(err)
+}
+
+// validateStream validates a file of newline delimited json objects line by line, so the file is not kept in memory.
+func validateStream(validator *vjson.Validator, file string, stdin io.Reader) (fileResult, error) {
+	reader, err := openInput(file, stdin)
+	if err != nil {
+		return fileResult{}, err
+	}
+	defer reader.Close()
+	streamResult, err := validator.ValidateStream(reader, vjson.StreamOptions{})
+	if err != nil {
+		return fileResult{}, err
+	}
+	return fileResult{File: file, Valid: streamResult.Invalid == 0, Lines: streamResult.Errors}, nil
+}
+
+// readValidator parses a schema file and compiles it.
+func readValidator(schemaPath string) (*vjson.Validator, error) {
+	schema, err := vjson.ReadFromFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Compile()
+}
+
+// errorMessages returns a message per error of a schema error, so each of them is written in a single line.
+func errorMessages(err error) []string {
+	multiError, ok := errors.Cause(err).(*multierror.Error)
+	if !ok {
+		return []string{err.Error()}
+	}
+	messages := make([]string, 0, len(multiError.Errors))
+	for _, e := range multiError.Errors {
+		messages = append(messages, e.Error())
+	}
+	return messages
+}
+
+func readInput(file string, stdin io.Reader) ([]byte, error) {
+	if file == stdinName {
+		return ioutil.ReadAll(stdin)
+	}
+	return ioutil.ReadFile(file)
+}
+
+func openInput(file string, stdin io.Reader) (io.ReadCloser, error) {
+	if file == stdinName {
+		return ioutil.NopCloser(stdin), nil
+	}
+	return os.Open(file)
+}
+
+func writeHuman(w io.Writer, result fileResult) {
+	if result.Valid {
+		fmt.Fprintf(w, "%s: ok\n", result.File)
+		return
+	}
+	for _, validationError := range result.Errors {
+		fmt.Fprintf(w, "%s: %s\n", result.File, validationError.Error())
+	}
+	for _, line := range result.Lines {
+		for _, validationError := range line.Errors {
+			fmt.Fprintf(w, "%s:%d: %s\n", result.File, line.Line, validationError.Error())
+		}
+	}
+}
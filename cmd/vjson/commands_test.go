@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{"fields": [{"name": "name", "type": "string", "required": true, "min_length": 2}, {"name": "age", "type": "integer", "min": 18}]}`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	assert.Nil(t, err)
+	return path
+}
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	exitCode := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	exitCode, _, stderr := runCommand("")
+	assert.Equal(t, exitError, exitCode)
+	assert.Contains(t, stderr, "Usage:")
+
+	exitCode, _, stderr = runCommand("", "lint")
+	assert.Equal(t, exitError, exitCode)
+	assert.Contains(t, stderr, `unknown command "lint"`)
+
+	exitCode, stdout, _ := runCommand("", "help")
+	assert.Equal(t, exitValid, exitCode)
+	assert.Contains(t, stdout, "Usage:")
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := writeFile(t, dir, "schema.json", testSchema)
+	validPath := writeFile(t, dir, "valid.json", `{"name": "John", "age": 20}`)
+	invalidPath := writeFile(t, dir, "invalid.json", `{"name": "J", "age": 10}`)
+
+	t.Run("valid", func(t *testing.T) {
+		exitCode, stdout, _ := runCommand("", "validate", "--schema", schemaPath, validPath)
+		assert.Equal(t, exitValid, exitCode)
+		assert.Equal(t, validPath+": ok\n", stdout)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		exitCode, stdout, _ := runCommand("", "validate", "--schema", schemaPath, validPath, invalidPath)
+		assert.Equal(t, exitInvalid, exitCode)
+		assert.Equal(t, validPath+": ok\n"+
+			invalidPath+": /name: Value for name field should have at least 2 characters\n"+
+			invalidPath+": /age: Value for age should be at least 18\n", stdout)
+	})
+	t.Run("stdin", func(t *testing.T) {
+		exitCode, stdout, _ := runCommand(`{"name": "John"}`, "validate", "--schema", schemaPath)
+		assert.Equal(t, exitValid, exitCode)
+		assert.Equal(t, "-: ok\n", stdout)
+
+		exitCode, _, _ = runCommand(`{}`, "validate", "--schema", schemaPath, "-")
+		assert.Equal(t, exitInvalid, exitCode)
+	})
+	t.Run("json_output", func(t *testing.T) {
+		exitCode, stdout, _ := runCommand("", "validate", "--schema", schemaPath, "--output", "json", validPath, invalidPath)
+		assert.Equal(t, exitInvalid, exitCode)
+
+		var results []fileResult
+		err := json.Unmarshal([]byte(stdout), &results)
+		assert.Nil(t, err)
+		assert.Len(t, results, 2)
+		assert.True(t, results[0].Valid)
+		assert.False(t, results[1].Valid)
+		assert.Len(t, results[1].Errors, 2)
+		assert.Equal(t, "/name", results[1].Errors[0].Path)
+		assert.Equal(t, "min_length", results[1].Errors[0].Rule)
+	})
+	t.Run("yaml_and_toml", func(t *testing.T) {
+		yamlPath := writeFile(t, dir, "config.yaml", "name: John\nage: 10\n")
+		tomlPath := writeFile(t, dir, "config.toml", "name = \"John\"\nage = 20\n")
+		exitCode, stdout, _ := runCommand("", "validate", "--schema", schemaPath, yamlPath, tomlPath)
+		assert.Equal(t, exitInvalid, exitCode)
+		assert.Equal(t, yamlPath+": line 2, column 6: /age: Value for age should be at least 18\n"+tomlPath+": ok\n", stdout)
+
+		exitCode, _, _ = runCommand("name: John\n", "validate", "--schema", schemaPath, "--input", "yaml")
+		assert.Equal(t, exitValid, exitCode)
+
+		exitCode, _, _ = runCommand("", "validate", "--schema", schemaPath, "--input", "xml")
+		assert.Equal(t, exitError, exitCode)
+	})
+	t.Run("ndjson", func(t *testing.T) {
+		streamPath := writeFile(t, dir, "events.ndjson", "{\"name\": \"John\"}\n{\"name\": \"J\", \"age\": 10}\n\n{\"name\": \"Jane\"}\n")
+		exitCode, stdout, _ := runCommand("", "validate", "--schema", schemaPath, streamPath)
+		assert.Equal(t, exitInvalid, exitCode)
+		assert.Equal(t, streamPath+":2: /name: Value for name field should have at least 2 characters\n"+
+			streamPath+":2: /age: Value for age should be at least 18\n", stdout)
+
+		exitCode, stdout, _ = runCommand("{\"name\": \"John\"}\n{}\n", "validate", "--schema", schemaPath, "--input", "ndjson", "--output", "json")
+		assert.Equal(t, exitInvalid, exitCode)
+		var results []fileResult
+		err := json.Unmarshal([]byte(stdout), &results)
+		assert.Nil(t, err)
+		assert.Len(t, results, 1)
+		assert.False(t, results[0].Valid)
+		assert.Len(t, results[0].Lines, 1)
+		assert.Equal(t, 2, results[0].Lines[0].Line)
+		assert.Equal(t, "/name", results[0].Lines[0].Errors[0].Path)
+
+		exitCode, stdout, _ = runCommand("{\"name\": \"John\"}\n", "validate", "--schema", schemaPath, "--input", "ndjson")
+		assert.Equal(t, exitValid, exitCode)
+		assert.Equal(t, "-: ok\n", stdout)
+	})
+	t.Run("missing_schema_flag", func(t *testing.T) {
+		exitCode, _, stderr := runCommand("", "validate", validPath)
+		assert.Equal(t, exitError, exitCode)
+		assert.Contains(t, stderr, "--schema flag is required")
+	})
+	t.Run("invalid_output", func(t *testing.T) {
+		exitCode, _, _ := runCommand("", "validate", "--schema", schemaPath, "--output", "xml", validPath)
+		assert.Equal(t, exitError, exitCode)
+	})
+	t.Run("invalid_schema", func(t *testing.T) {
+		exitCode, _, stderr := runCommand("", "validate", "--schema", filepath.Join(dir, "missing.json"), validPath)
+		assert.Equal(t, exitError, exitCode)
+		assert.Contains(t, stderr, "missing.json")
+	})
+	t.Run("missing_file", func(t *testing.T) {
+		exitCode, stdout, stderr := runCommand("", "validate", "--schema", schemaPath, filepath.Join(dir, "missing.json"), validPath)
+		assert.Equal(t, exitError, exitCode)
+		assert.Contains(t, stderr, "missing.json")
+		assert.Equal(t, validPath+": ok\n", stdout)
+	})
+}
+
+func TestCheckSchema(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := writeFile(t, dir, "schema.json", testSchema)
+	invalidPath := writeFile(t, dir, "invalid.json", `{"fields": [{"name": "foo", "type": "date"}]}`)
+	invalidFormatPath := writeFile(t, dir, "format.json", `{"fields": [{"name": "foo", "type": "string", "format": "("}]}`)
+
+	exitCode, stdout, _ := runCommand("", "check-schema", schemaPath)
+	assert.Equal(t, exitValid, exitCode)
+	assert.Equal(t, schemaPath+": ok\n", stdout)
+
+	exitCode, stdout, _ = runCommand("", "check-schema", schemaPath, invalidPath, invalidFormatPath)
+	assert.Equal(t, exitInvalid, exitCode)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, invalidPath+": Invalid type: date", lines[1])
+	assert.Contains(t, lines[2], invalidFormatPath+": Invalid StringField format string for field foo")
+
+	exitCode, _, _ = runCommand("", "check-schema")
+	assert.Equal(t, exitError, exitCode)
+}
@@ -0,0 +1,22 @@
+// Command vjson validates json files with a vjson schema.
+//
+// Usage:
+//
+//	vjson validate --schema schema.json [--output human|json] [--input json|yaml|toml|ndjson] [file ...]
+//	vjson check-schema schema.json ...
+//
+// validate reads json objects from the given files, or from standard input if no file is given or a file is "-".
+// .yaml, .yml and .toml files are validated as YAML and TOML documents, unless --input flag sets the format.
+// .ndjson and .jsonl files, or any file with --input ndjson, are streams of newline delimited json objects, which are
+// validated line by line without reading the whole file into memory.
+// check-schema parses and compiles schema files without validating any data.
+//
+// Exit code is 0 if everything is valid, 1 if a json object or schema is invalid and 2 if the command could not run,
+// e.g. because of an invalid flag or a file which could not be read.
+package main
+
+import "os"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,148 @@
+package vjson
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// MapOption configures ValidateMap.
+type MapOption func(*mapOptions)
+
+type mapOptions struct {
+	coercion bool
+}
+
+// WithCoercion makes ValidateMap convert strings to the type of their fields before validation, e.g. "42" to an
+// integer for an integer field. it is used for values which arrive as strings, like query parameters and form data.
+// strings which can not be converted are kept, and they are reported by the validation.
+func WithCoercion() MapOption {
+	return func(options *mapOptions) {
+		options.coercion = true
+	}
+}
+
+// ValidateMap validates a decoded json object according to the Schema and returns it. with WithCoercion, strings are
+// converted according to the types of their fields, and the converted object is returned.
+// the input is not modified, and nil is returned with the validation error if the object is invalid.
+func (s *Schema) ValidateMap(values map[string]interface{}, opts ...MapOption) (map[string]interface{}, error) {
+	var options mapOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.coercion {
+		values = s.coerceObject(values)
+	}
+	err := s.ValidateValue(values)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ValidateValues validates query parameters or form data according to the Schema with coercion, and returns the
+// converted values. all values of a parameter are used for an array field, and the first value is used for other
+// fields. parameters with more than one value are kept as arrays for other fields, so they are reported as invalid.
+func (s *Schema) ValidateValues(values url.Values) (map[string]interface{}, error) {
+	object := make(map[string]interface{}, len(values))
+	for name, parameterValues := range values {
+		items := make([]interface{}, 0, len(parameterValues))
+		for _, value := range parameterValues {
+			items = append(items, value)
+		}
+		field := s.propertyField(name)
+		if ref, ok := field.(*RefField); ok {
+			field, _ = ref.definition()
+		}
+		if _, ok := field.(*ArrayField); ok || len(items) != 1 {
+			object[name] = items
+			continue
+		}
+		object[name] = items[0]
+	}
+	return s.ValidateMap(object, WithCoercion())
+}
+
+// coerceObject returns a copy of an object whose strings are converted according to the types of their fields.
+func (s *Schema) coerceObject(values map[string]interface{}) map[string]interface{} {
+	if s.root != nil {
+		coerced, ok := coerceValue(s.root, values).(map[string]interface{})
+		if ok {
+			return coerced
+		}
+		return values
+	}
+	object := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		field := s.propertyField(name)
+		if field == nil {
+			object[name] = value
+			continue
+		}
+		object[name] = coerceValue(field, value)
+	}
+	return object
+}
+
+// coerceValue converts a string to the type of a field, and converts the values nested in objects and arrays.
+// values which can not be converted are returned unchanged.
+func coerceValue(field Field, value interface{}) interface{} {
+	switch f := field.(type) {
+	case *IntegerField:
+		// strings are parsed like json numbers of the field, so big integers and numbers like 1e3 are converted too.
+		// integers which do not fit in int are kept as json.Number.
+		if text, ok := value.(string); ok && decimalPattern.MatchString(text) {
+			if number, ok := parseInteger(text); ok && number != nil {
+				if actual, ok := integerActual(number).(int); ok {
+					return actual
+				}
+				return json.Number(text)
+			}
+		}
+	case *FloatField:
+		if text, ok := value.(string); ok {
+			// NaN and infinities are not json numbers
+			if number, err := strconv.ParseFloat(text, 64); err == nil && !math.IsNaN(number) && !math.IsInf(number, 0) {
+				return number
+			}
+		}
+	case *DecimalField:
+		if text, ok := value.(string); ok {
+			if _, ok := parseDecimal(text); ok {
+				return json.Number(text)
+			}
+		}
+	case *BooleanField:
+		if text, ok := value.(string); ok {
+			if boolean, err := strconv.ParseBool(text); err == nil {
+				return boolean
+			}
+		}
+	case *ArrayField:
+		switch items := value.(type) {
+		case []interface{}:
+			coerced := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				coerced = append(coerced, coerceValue(f.items, item))
+			}
+			return coerced
+		case []string:
+			coerced := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				coerced = append(coerced, coerceValue(f.items, item))
+			}
+			return coerced
+		}
+	case *ObjectField:
+		if object, ok := value.(map[string]interface{}); ok {
+			return f.schema.coerceObject(object)
+		}
+	case *RefField:
+		definition, err := f.definition()
+		if err == nil {
+			return coerceValue(definition, value)
+		}
+	}
+	return value
+}
@@ -0,0 +1,128 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestSchema_Coercion(t *testing.T) {
+	schema := NewSchema(
+		Integer("page").Min(1).Required(),
+		Float("ratio"),
+		Boolean("active"),
+		String("query"),
+		Array("ids", Integer("id")),
+		Object("filter", NewSchema(Integer("year"), Boolean("archived"))),
+	)
+
+	t.Run("coercion", func(t *testing.T) {
+		input := map[string]interface{}{
+			"page":   "2",
+			"ratio":  "0.5",
+			"active": "true",
+			"query":  "42",
+			"ids":    []interface{}{"1", "2"},
+			"filter": map[string]interface{}{"year": "2021", "archived": "false"},
+			"extra":  "1",
+		}
+		values, err := schema.ValidateMap(input, WithCoercion())
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"page":   2,
+			"ratio":  0.5,
+			"active": true,
+			"query":  "42",
+			"ids":    []interface{}{1, 2},
+			"filter": map[string]interface{}{"year": 2021, "archived": false},
+			"extra":  "1",
+		}, values)
+		assert.Equal(t, "2", input["page"])
+	})
+	t.Run("invalid", func(t *testing.T) {
+		values, err := schema.ValidateMap(map[string]interface{}{"page": "0", "ratio": "NaN", "active": "yes"}, WithCoercion())
+		assert.Nil(t, values)
+		errs := toValidationErrors(err)
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "/page", errs[0].Path)
+		assert.Equal(t, "min", errs[0].Rule)
+		assert.Equal(t, "/ratio", errs[1].Path)
+		assert.Equal(t, "type", errs[1].Rule)
+		assert.Equal(t, "/active", errs[2].Path)
+	})
+	t.Run("without_coercion", func(t *testing.T) {
+		_, err := schema.ValidateMap(map[string]interface{}{"page": "2"})
+		assert.NotNil(t, err)
+
+		values, err := schema.ValidateMap(map[string]interface{}{"page": 2})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]interface{}{"page": 2}, values)
+	})
+	t.Run("integers", func(t *testing.T) {
+		schema := NewSchema(Integer("count"), Integer("total").Big(), Integer("limit"), Integer("offset"))
+		values, err := schema.ValidateMap(map[string]interface{}{
+			"count": "1e3",
+			"total": "99999999999999999999",
+			"limit": "2.0",
+		}, WithCoercion())
+		assert.Nil(t, err)
+		assert.Equal(t, 1000, values["count"])
+		assert.Equal(t, json.Number("99999999999999999999"), values["total"])
+		assert.Equal(t, 2, values["limit"])
+
+		values, err = schema.ValidateMap(map[string]interface{}{"count": "1.5", "limit": "0x10", "offset": "99999999999999999999"}, WithCoercion())
+		assert.Nil(t, values)
+		errs := toValidationErrors(err)
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "/count", errs[0].Path)
+		assert.Equal(t, "type", errs[0].Rule)
+		assert.Equal(t, "/limit", errs[1].Path)
+		assert.Equal(t, "type", errs[1].Rule)
+		assert.Equal(t, "/offset", errs[2].Path)
+	})
+	t.Run("ref", func(t *testing.T) {
+		schema := NewSchema(Ref("page", "page")).Define("page", Integer("page").Min(1))
+		values, err := schema.ValidateMap(map[string]interface{}{"page": "3"}, WithCoercion())
+		assert.Nil(t, err)
+		assert.Equal(t, 3, values["page"])
+	})
+}
+
+func TestSchema_ValidateValues(t *testing.T) {
+	schema := NewSchema(
+		Integer("page").Min(1).Required(),
+		Boolean("active"),
+		Array("tags", String("tag")),
+		Array("ids", Integer("id")),
+	)
+
+	query, err := url.ParseQuery("page=2&active=1&tags=a&ids=1&ids=2")
+	assert.Nil(t, err)
+	values, err := schema.ValidateValues(query)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"page":   2,
+		"active": true,
+		"tags":   []interface{}{"a"},
+		"ids":    []interface{}{1, 2},
+	}, values)
+
+	query, err = url.ParseQuery("page=1&page=2&ids=x")
+	assert.Nil(t, err)
+	errs := toValidationErrors(func() error {
+		_, err := schema.ValidateValues(query)
+		return err
+	}())
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "/page", errs[0].Path)
+	assert.Equal(t, "/ids/0", errs[1].Path)
+
+	t.Run("validator", func(t *testing.T) {
+		validator, err := schema.Compile()
+		assert.Nil(t, err)
+		values, err := validator.ValidateValues(url.Values{"page": {"5"}})
+		assert.Nil(t, err)
+		assert.Equal(t, 5, values["page"])
+	})
+}
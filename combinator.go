@@ -0,0 +1,224 @@
+package vjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"strings"
+)
+
+// CombinatorField is the type for validating a value of a JSON against a combination of fields.
+// it is created by OneOf, AnyOf, AllOf and Not constructors.
+type CombinatorField struct {
+	name     string
+	required bool
+	kind     fieldType
+	fields   []Field
+
+	validators   customValidators
+	defaultValue interface{}
+}
+
+// To Force Implementing Field interface by CombinatorField
+var _ Field = (*CombinatorField)(nil)
+
+// GetName returns name of the field
+func (c *CombinatorField) GetName() string {
+	return c.name
+}
+
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (c *CombinatorField) Custom(name string, fn ValidatorFunc) *CombinatorField {
+	c.validators = c.validators.add(name, fn)
+	return c
+}
+
+func (c *CombinatorField) getValidators() customValidators {
+	return c.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (c *CombinatorField) Default(value interface{}) *CombinatorField {
+	c.defaultValue = value
+	return c
+}
+
+func (c *CombinatorField) getDefault() (interface{}, bool) {
+	return c.defaultValue, c.defaultValue != nil
+}
+
+// Validate is used for validating a value. it returns an error if the value is invalid.
+func (c *CombinatorField) Validate(v interface{}) error {
+	err := c.validateValue(v)
+	if err != nil || v == nil {
+		return err
+	}
+	return c.validators.validate(c.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (c *CombinatorField) validateValue(v interface{}) error {
+	if v == nil {
+		if !c.required {
+			return nil
+		}
+		return requiredError(c.name)
+	}
+	return c.validateBranches(v, func(field Field) error {
+		return validateField(field, v)
+	})
+}
+
+func (c *CombinatorField) validateResult(value gjson.Result) error {
+	if !value.Exists() {
+		return c.Validate(nil)
+	}
+	return c.validateBranches(value.Value(), func(field Field) error {
+		return validateResult(field, value)
+	})
+}
+
+// validateBranches validates the value with every branch field and checks the number of matched branches.
+func (c *CombinatorField) validateBranches(v interface{}, validate func(field Field) error) error {
+	var matched []int
+	var causes []ValidationError
+	var messages []string
+	for index, field := range c.fields {
+		err := validate(field)
+		if err == nil {
+			matched = append(matched, index)
+			continue
+		}
+		branchErrors := toValidationErrors(err)
+		causes = append(causes, branchErrors...)
+		for _, branchError := range branchErrors {
+			messages = append(messages, fmt.Sprintf("%d (%s): %s", index, field.GetName(), branchError.Error()))
+		}
+	}
+	reasons := strings.Join(messages, "; ")
+
+	switch c.kind {
+	case oneOfType:
+		if len(matched) == 0 {
+			return c.newError(v, causes, "Value for %s should match exactly one of the fields, but it matched none: [%s]", c.name, reasons)
+		}
+		if len(matched) > 1 {
+			return c.newError(v, nil, "Value for %s should match exactly one of the fields, but it matched fields %v", c.name, matched)
+		}
+	case anyOfType:
+		if len(matched) == 0 {
+			return c.newError(v, causes, "Value for %s should match at least one of the fields, but it matched none: [%s]", c.name, reasons)
+		}
+	case allOfType:
+		if len(matched) != len(c.fields) {
+			return c.newError(v, causes, "Value for %s should match all of the fields: [%s]", c.name, reasons)
+		}
+	case notType:
+		if len(matched) > 0 {
+			return c.newError(v, nil, "Value for %s should not match field %s", c.name, c.fields[0].GetName())
+		}
+	}
+	return nil
+}
+
+func (c *CombinatorField) newError(v interface{}, causes []ValidationError, format string, args ...interface{}) *ValidationError {
+	validationError := newValidationError(c.name, string(c.kind), nil, v, format, args...)
+	validationError.Causes = causes
+	return validationError
+}
+
+func (c *CombinatorField) compile() error {
+	if c.kind == notType && len(c.fields) != 1 {
+		return errors.Errorf("not field %s should have exactly one field", c.name)
+	}
+	if len(c.fields) == 0 {
+		return errors.Errorf("%s field %s should have at least one field", c.kind, c.name)
+	}
+	for index, field := range c.fields {
+		err := compileField(field)
+		if err != nil {
+			return errors.Wrapf(err, "field %d of %s field %s is invalid", index, c.kind, c.name)
+		}
+	}
+	return nil
+}
+
+// Required is called to make a field required in a JSON
+func (c *CombinatorField) Required() *CombinatorField {
+	c.required = true
+	return c
+}
+
+func (c *CombinatorField) MarshalJSON() ([]byte, error) {
+	fields := make([]map[string]interface{}, 0, len(c.fields))
+	for _, field := range c.fields {
+		fieldRaw, err := json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not marshal fields of %s field: %s", c.kind, c.name)
+		}
+
+		fieldSpec := make(map[string]interface{})
+		err = decodeSpec(fieldRaw, &fieldSpec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal fields of %s field: %s", c.kind, c.name)
+		}
+		fields = append(fields, fieldSpec)
+	}
+
+	spec := CombinatorFieldSpec{
+		Name:       c.name,
+		Type:       c.kind,
+		Required:   c.required,
+		Validators: c.validators.names(),
+		Default:    c.defaultValue,
+	}
+	if c.kind == notType && len(fields) == 1 {
+		spec.Field = fields[0]
+	} else {
+		spec.Fields = fields
+	}
+	return json.Marshal(spec)
+}
+
+// OneOf is the constructor of a field which value should match exactly one of the given fields.
+func OneOf(name string, fields ...Field) *CombinatorField {
+	return &CombinatorField{
+		name:     name,
+		required: false,
+		kind:     oneOfType,
+		fields:   fields,
+	}
+}
+
+// AnyOf is the constructor of a field which value should match at least one of the given fields.
+func AnyOf(name string, fields ...Field) *CombinatorField {
+	return &CombinatorField{
+		name:     name,
+		required: false,
+		kind:     anyOfType,
+		fields:   fields,
+	}
+}
+
+// AllOf is the constructor of a field which value should match all of the given fields.
+func AllOf(name string, fields ...Field) *CombinatorField {
+	return &CombinatorField{
+		name:     name,
+		required: false,
+		kind:     allOfType,
+		fields:   fields,
+	}
+}
+
+// Not is the constructor of a field which value should not match the given field.
+func Not(name string, field Field) *CombinatorField {
+	return &CombinatorField{
+		name:     name,
+		required: false,
+		kind:     notType,
+		fields:   []Field{field},
+	}
+}
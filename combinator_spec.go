@@ -0,0 +1,24 @@
+package vjson
+
+// CombinatorFieldSpec is a type used for parsing a CombinatorField
+type CombinatorFieldSpec struct {
+	Name       string                   `mapstructure:"name" json:"name"`
+	Type       fieldType                `json:"type"`
+	Required   bool                     `mapstructure:"required" json:"required,omitempty"`
+	Fields     []map[string]interface{} `mapstructure:"fields" json:"fields,omitempty"`
+	Field      map[string]interface{}   `mapstructure:"field" json:"field,omitempty"`
+	Validators []string                 `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{}              `mapstructure:"default" json:"default,omitempty"`
+}
+
+// NewCombinator receives a CombinatorFieldSpec and its parsed fields and returns a CombinatorField
+func NewCombinator(spec CombinatorFieldSpec, fields []Field) *CombinatorField {
+	return &CombinatorField{
+		name:         spec.Name,
+		required:     spec.Required,
+		kind:         spec.Type,
+		fields:       fields,
+		validators:   namedValidators(spec.Validators),
+		defaultValue: spec.Default,
+	}
+}
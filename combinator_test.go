@@ -0,0 +1,133 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCombinatorField_GetName(t *testing.T) {
+	field := OneOf("foo", String("bar"), Integer("baz"))
+	assert.Equal(t, "foo", field.GetName())
+}
+
+func TestCombinatorField_Validate(t *testing.T) {
+	t.Run("not_required_field", func(t *testing.T) {
+		field := OneOf("foo", String("bar"), Integer("baz"))
+
+		err := field.Validate(nil)
+		assert.Nil(t, err)
+	})
+	t.Run("required_field", func(t *testing.T) {
+		field := OneOf("foo", String("bar"), Integer("baz")).Required()
+
+		err := field.Validate(nil)
+		assert.NotNil(t, err)
+	})
+	t.Run("one_of", func(t *testing.T) {
+		field := OneOf("foo", String("bar"), Integer("baz"), Integer("qux").Min(10))
+
+		err := field.Validate("hello")
+		assert.Nil(t, err)
+
+		err = field.Validate(5)
+		assert.Nil(t, err)
+
+		err = field.Validate(true)
+		assert.NotNil(t, err)
+
+		err = field.Validate(20)
+		assert.NotNil(t, err)
+	})
+	t.Run("any_of", func(t *testing.T) {
+		field := AnyOf("foo", String("bar"), Integer("baz"), Integer("qux").Min(10))
+
+		err := field.Validate(20)
+		assert.Nil(t, err)
+
+		err = field.Validate(true)
+		assert.NotNil(t, err)
+	})
+	t.Run("all_of", func(t *testing.T) {
+		field := AllOf("foo", Integer("bar").Min(10), Integer("baz").Max(20))
+
+		err := field.Validate(15)
+		assert.Nil(t, err)
+
+		err = field.Validate(25)
+		assert.NotNil(t, err)
+	})
+	t.Run("not", func(t *testing.T) {
+		field := Not("foo", String("bar").Choices("admin", "root"))
+
+		err := field.Validate("john")
+		assert.Nil(t, err)
+
+		err = field.Validate("admin")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestCombinatorField_ValidationErrors(t *testing.T) {
+	schema := NewSchema(
+		OneOf("id",
+			String("id_string").MinLength(3),
+			Object("id_object", NewSchema(Integer("value").Required())),
+		),
+	)
+
+	errs := schema.ValidateDetailed([]byte(`{"id": {"val": 1}}`))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/id", errs[0].Path)
+	assert.Equal(t, "one_of", errs[0].Rule)
+	assert.Len(t, errs[0].Causes, 2)
+	assert.Equal(t, "id_string", errs[0].Causes[0].Field)
+	assert.Equal(t, "/id", errs[0].Causes[0].Path)
+	assert.Equal(t, "type", errs[0].Causes[0].Rule)
+	assert.Equal(t, "/id/value", errs[0].Causes[1].Path)
+	assert.Equal(t, "required", errs[0].Causes[1].Rule)
+	assert.Contains(t, errs[0].Message, "1 (id_object)")
+
+	errs = schema.ValidateDetailed([]byte(`{"id": {"value": 1}}`))
+	assert.Len(t, errs, 0)
+}
+
+func TestCombinatorField_MarshalJSON(t *testing.T) {
+	field := OneOf("foo", String("bar"), Integer("baz")).Required()
+
+	b, err := json.Marshal(field)
+	assert.Nil(t, err)
+
+	data := map[string]interface{}{}
+	err = json.Unmarshal(b, &data)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "foo", data["name"])
+	assert.Equal(t, string(oneOfType), data["type"])
+	assert.Len(t, data["fields"], 2)
+
+	var schema Schema
+	err = json.Unmarshal([]byte(`{"fields":[`+string(b)+`]}`), &schema)
+	assert.Nil(t, err)
+	assert.Equal(t, field.kind, schema.Fields[0].(*CombinatorField).kind)
+	assert.Equal(t, field.required, schema.Fields[0].(*CombinatorField).required)
+	assert.Len(t, schema.Fields[0].(*CombinatorField).fields, 2)
+
+	b, err = json.Marshal(Not("foo", String("bar")))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name":"foo","type":"not","field":{"name":"bar","type":"string"}}`, string(b))
+}
+
+func TestNewCombinator(t *testing.T) {
+	field := NewCombinator(CombinatorFieldSpec{
+		Name:     "bar",
+		Type:     anyOfType,
+		Required: true,
+	}, []Field{String("baz")})
+
+	assert.NotNil(t, field)
+	assert.Equal(t, "bar", field.name)
+	assert.Equal(t, anyOfType, field.kind)
+	assert.Equal(t, true, field.required)
+	assert.Len(t, field.fields, 1)
+}
@@ -0,0 +1,445 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// ComparisonOperator is the operator of a Comparison.
+type ComparisonOperator string
+
+const (
+	LessThanOperator       ComparisonOperator = "less_than"
+	LessOrEqualOperator    ComparisonOperator = "less_or_equal"
+	GreaterThanOperator    ComparisonOperator = "greater_than"
+	GreaterOrEqualOperator ComparisonOperator = "greater_or_equal"
+	EqualOperator          ComparisonOperator = "equal"
+	NotEqualOperator       ComparisonOperator = "not_equal"
+)
+
+var comparisonOperatorTexts = map[ComparisonOperator]string{
+	LessThanOperator:       "less than",
+	LessOrEqualOperator:    "less than or equal to",
+	GreaterThanOperator:    "greater than",
+	GreaterOrEqualOperator: "greater than or equal to",
+	EqualOperator:          "equal to",
+	NotEqualOperator:       "not equal to",
+}
+
+// holds reports whether the operator accepts the result of comparing two values, which is -1, 0 or +1.
+func (o ComparisonOperator) holds(cmp int) bool {
+	switch o {
+	case LessThanOperator:
+		return cmp < 0
+	case LessOrEqualOperator:
+		return cmp <= 0
+	case GreaterThanOperator:
+		return cmp > 0
+	case GreaterOrEqualOperator:
+		return cmp >= 0
+	case EqualOperator:
+		return cmp == 0
+	case NotEqualOperator:
+		return cmp != 0
+	}
+	return false
+}
+
+type operandKind string
+
+const (
+	valueOperand operandKind = "value"
+	sumOperand   operandKind = "sum"
+	countOperand operandKind = "count"
+)
+
+// Operand is a side of a Comparison. it is created by FieldValue, Sum and Count constructors.
+type Operand struct {
+	kind   operandKind
+	fields []string
+}
+
+// FieldValue is an operand of the value of a field. numbers are compared by their values, and strings
+// lexicographically, e.g. dates in RFC 3339 format. other values can only be compared with equal and not_equal.
+func FieldValue(name string) Operand {
+	return Operand{kind: valueOperand, fields: []string{name}}
+}
+
+// Sum is an operand of the sum of numeric fields, e.g. Sum("subtotal", "tax").
+func Sum(names ...string) Operand {
+	return Operand{kind: sumOperand, fields: names}
+}
+
+// Count is an operand of the number of items of an array field, or the number of properties of an object field.
+func Count(name string) Operand {
+	return Operand{kind: countOperand, fields: []string{name}}
+}
+
+// operandValue is the evaluated value of an operand. number is set for numbers, sums and counts.
+type operandValue struct {
+	number *big.Rat
+	value  gjson.Result
+}
+
+func (v operandValue) actual() interface{} {
+	if v.number != nil {
+		number, _ := v.number.Float64()
+		return number
+	}
+	return v.value.Value()
+}
+
+// evaluate returns the value of the operand in a json object. it returns false if a field of the operand is missing
+// or null, since presence of fields is checked by their own rules.
+func (o Operand) evaluate(values map[string]gjson.Result) (operandValue, bool, error) {
+	for _, name := range o.fields {
+		if value, found := values[name]; !found || value.Type == gjson.Null {
+			return operandValue{}, false, nil
+		}
+	}
+
+	switch o.kind {
+	case valueOperand:
+		value := values[o.fields[0]]
+		if value.Type == gjson.Number {
+			number, err := parseNumber(o.fields[0], value)
+			if err != nil {
+				return operandValue{}, false, err
+			}
+			return operandValue{number: number, value: value}, true, nil
+		}
+		return operandValue{value: value}, true, nil
+	case sumOperand:
+		sum := new(big.Rat)
+		for _, name := range o.fields {
+			value := values[name]
+			if value.Type != gjson.Number {
+				return operandValue{}, false, errors.Errorf("Value of %s could not be summed, it is not a number", name)
+			}
+			number, err := parseNumber(name, value)
+			if err != nil {
+				return operandValue{}, false, err
+			}
+			sum.Add(sum, number)
+		}
+		return operandValue{number: sum}, true, nil
+	case countOperand:
+		value := values[o.fields[0]]
+		if !value.IsArray() && !value.IsObject() {
+			return operandValue{}, false, errors.Errorf("Value of %s could not be counted, it is not an array or an object", o.fields[0])
+		}
+		count := 0
+		value.ForEach(func(_, _ gjson.Result) bool {
+			count++
+			return true
+		})
+		return operandValue{number: new(big.Rat).SetInt64(int64(count))}, true, nil
+	}
+	return operandValue{}, false, errors.Errorf("invalid operand kind: %s", o.kind)
+}
+
+// parseNumber returns the exact value of a json number, so sums of decimals like 0.1 and 0.2 are not rounded.
+// numbers with large exponents are out of range, since their exact value could be too large to compute.
+func parseNumber(name string, value gjson.Result) (*big.Rat, error) {
+	if exponentOutOfRange(value.Raw) {
+		return nil, errors.Errorf("Value of %s is out of range, its exponent should be at most %d", name, maxExponent)
+	}
+	number, ok := new(big.Rat).SetString(value.Raw)
+	if !ok {
+		return nil, errors.Errorf("Value of %s is not a valid number", name)
+	}
+	return number, nil
+}
+
+func (o Operand) check() error {
+	if len(o.fields) == 0 {
+		return errors.Errorf("%s operand should have at least one field", o.kind)
+	}
+	for _, name := range o.fields {
+		if name == "" {
+			return errors.Errorf("%s operand has a field without name", o.kind)
+		}
+	}
+	return nil
+}
+
+// describe returns the description of the operand in error messages, e.g. sum of subtotal, tax.
+func (o Operand) describe() string {
+	switch o.kind {
+	case sumOperand:
+		return "sum of " + strings.Join(o.fields, ", ")
+	case countOperand:
+		return "count of " + o.fields[0]
+	}
+	return "value of " + strings.Join(o.fields, ", ")
+}
+
+// MarshalJSON is implemented for serializing an Operand. a value operand is the name of its field.
+func (o Operand) MarshalJSON() ([]byte, error) {
+	if len(o.fields) == 0 {
+		return nil, errors.New("operand should have at least one field")
+	}
+	switch o.kind {
+	case sumOperand:
+		return json.Marshal(map[string]interface{}{"sum": o.fields})
+	case countOperand:
+		return json.Marshal(map[string]interface{}{"count": o.fields[0]})
+	}
+	return json.Marshal(o.fields[0])
+}
+
+// getOperand parses the spec of an operand, which is either a field name or an object with sum or count key.
+func getOperand(spec interface{}) (Operand, error) {
+	switch s := spec.(type) {
+	case string:
+		return FieldValue(s), nil
+	case map[string]interface{}:
+		if len(s) != 1 {
+			return Operand{}, errors.Errorf("operand should have exactly one of sum or count keys")
+		}
+		if names, found := s["sum"]; found {
+			rawNames, ok := names.([]interface{})
+			if !ok {
+				return Operand{}, errors.Errorf("sum key of operand should be a list of field names")
+			}
+			fields := make([]string, 0, len(rawNames))
+			for _, rawName := range rawNames {
+				name, ok := rawName.(string)
+				if !ok {
+					return Operand{}, errors.Errorf("sum key of operand should be a list of field names")
+				}
+				fields = append(fields, name)
+			}
+			return Sum(fields...), nil
+		}
+		if name, found := s["count"]; found {
+			fieldName, ok := name.(string)
+			if !ok {
+				return Operand{}, errors.Errorf("count key of operand should be a field name")
+			}
+			return Count(fieldName), nil
+		}
+		return Operand{}, errors.Errorf("operand should have exactly one of sum or count keys")
+	}
+	return Operand{}, errors.Errorf("operand should be a field name or an object")
+}
+
+// Comparison is a rule of a schema which compares two operands of a json object, e.g. two of its fields.
+type Comparison struct {
+	left     Operand
+	operator ComparisonOperator
+	right    Operand
+}
+
+// ComparisonSpec is used for parsing a Comparison. Left and Right are either field names,
+// or objects like {"sum": ["subtotal", "tax"]} and {"count": "items"}.
+type ComparisonSpec struct {
+	Left     interface{}        `json:"left"`
+	Operator ComparisonOperator `json:"operator"`
+	Right    interface{}        `json:"right"`
+}
+
+type comparisonJSON struct {
+	Left     Operand            `json:"left"`
+	Operator ComparisonOperator `json:"operator"`
+	Right    Operand            `json:"right"`
+}
+
+// MarshalJSON is implemented for serializing a Comparison in the same format which is parsed by a schema.
+func (c *Comparison) MarshalJSON() ([]byte, error) {
+	return json.Marshal(comparisonJSON{Left: c.left, Operator: c.operator, Right: c.right})
+}
+
+func (c *Comparison) check() error {
+	if _, found := comparisonOperatorTexts[c.operator]; !found {
+		return errors.Errorf("invalid comparison operator: %s", c.operator)
+	}
+	err := c.left.check()
+	if err != nil {
+		return errors.Wrap(err, "left operand is invalid")
+	}
+	err = c.right.check()
+	if err != nil {
+		return errors.Wrap(err, "right operand is invalid")
+	}
+	return nil
+}
+
+// validate compares the operands of the comparison in a json object. the comparison is skipped if a field of its
+// operands is missing or null, or if it is already rejected by its own rules.
+func (c *Comparison) validate(values map[string]gjson.Result, invalid map[string]bool) error {
+	err := c.check()
+	if err != nil {
+		return err
+	}
+	for _, operand := range []Operand{c.left, c.right} {
+		for _, name := range operand.fields {
+			if invalid[name] {
+				return nil
+			}
+		}
+	}
+
+	left, leftFound, err := c.left.evaluate(values)
+	if err != nil {
+		return c.newError(nil, nil, "%s", err.Error())
+	}
+	right, rightFound, err := c.right.evaluate(values)
+	if err != nil {
+		return c.newError(nil, nil, "%s", err.Error())
+	}
+	if !leftFound || !rightFound {
+		return nil
+	}
+
+	cmp, comparable := compareValues(left, right, c.operator)
+	if !comparable {
+		return c.newError(right.actual(), left.actual(), "%s could not be compared with %s", capitalize(c.left.describe()), c.right.describe())
+	}
+	if !c.operator.holds(cmp) {
+		return c.newError(right.actual(), left.actual(), "%s should be %s %s", capitalize(c.left.describe()), comparisonOperatorTexts[c.operator], c.right.describe())
+	}
+	return nil
+}
+
+// compareValues compares two operand values. values which are not numbers or strings are only compared for equality.
+func compareValues(left, right operandValue, operator ComparisonOperator) (int, bool) {
+	if left.number != nil && right.number != nil {
+		return left.number.Cmp(right.number), true
+	}
+	if left.value.Type == gjson.String && right.value.Type == gjson.String {
+		return strings.Compare(left.value.Str, right.value.Str), true
+	}
+	if operator != EqualOperator && operator != NotEqualOperator {
+		return 0, false
+	}
+	if reflect.DeepEqual(left.actual(), right.actual()) {
+		return 0, true
+	}
+	return 1, true
+}
+
+// newError returns a ValidationError of the comparison. its path is the first field of the left operand,
+// and the other fields of the comparison are its related paths.
+func (c *Comparison) newError(expected, actual interface{}, format string, args ...interface{}) error {
+	err := newValidationError(c.left.fields[0], string(c.operator), expected, actual, format, args...)
+	err.Path = "/" + escapePointerToken(c.left.fields[0])
+	for _, name := range append(append([]string{}, c.left.fields[1:]...), c.right.fields...) {
+		err.RelatedPaths = append(err.RelatedPaths, "/"+escapePointerToken(name))
+	}
+	return err
+}
+
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// Compare adds a comparison of two operands to the schema. e.g. schema.Compare(Sum("subtotal", "tax"),
+// EqualOperator, FieldValue("total")) checks that total is the sum of subtotal and tax.
+// comparisons are validated after the fields of the schema, and they are skipped if a field of their operands is
+// missing, null or invalid.
+func (s Schema) Compare(left Operand, operator ComparisonOperator, right Operand) Schema {
+	s.comparisons = append(append([]*Comparison{}, s.comparisons...), &Comparison{left: left, operator: operator, right: right})
+	return s
+}
+
+// LessThan checks that the value of left field is less than the value of right field, e.g. LessThan("start", "end").
+func (s Schema) LessThan(left, right string) Schema {
+	return s.Compare(FieldValue(left), LessThanOperator, FieldValue(right))
+}
+
+// LessOrEqual checks that the value of left field is less than or equal to the value of right field.
+func (s Schema) LessOrEqual(left, right string) Schema {
+	return s.Compare(FieldValue(left), LessOrEqualOperator, FieldValue(right))
+}
+
+// GreaterThan checks that the value of left field is greater than the value of right field.
+func (s Schema) GreaterThan(left, right string) Schema {
+	return s.Compare(FieldValue(left), GreaterThanOperator, FieldValue(right))
+}
+
+// GreaterOrEqual checks that the value of left field is greater than or equal to the value of right field.
+func (s Schema) GreaterOrEqual(left, right string) Schema {
+	return s.Compare(FieldValue(left), GreaterOrEqualOperator, FieldValue(right))
+}
+
+// Equal checks that the values of two fields are equal, e.g. Equal("password", "password_confirm").
+func (s Schema) Equal(left, right string) Schema {
+	return s.Compare(FieldValue(left), EqualOperator, FieldValue(right))
+}
+
+// NotEqual checks that the values of two fields are not equal.
+func (s Schema) NotEqual(left, right string) Schema {
+	return s.Compare(FieldValue(left), NotEqualOperator, FieldValue(right))
+}
+
+// validateComparisons validates a json object with comparisons of the schema.
+// invalid is the set of fields which are rejected by their own rules.
+func (s *Schema) validateComparisons(json gjson.Result, invalid map[string]bool) error {
+	if len(s.comparisons) == 0 || !json.IsObject() {
+		return nil
+	}
+	values := make(map[string]gjson.Result)
+	json.ForEach(func(key, value gjson.Result) bool {
+		values[key.String()] = value
+		return true
+	})
+	return s.compare(values, invalid)
+}
+
+// compare validates the values of object properties with comparisons of the schema.
+func (s *Schema) compare(values map[string]gjson.Result, invalid map[string]bool) error {
+	var result error
+	for _, comparison := range s.comparisons {
+		err := comparison.validate(values, invalid)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// setComparisonsSpec parses the comparisons key of a schema spec.
+func (s *Schema) setComparisonsSpec(comparisonSpecs []ComparisonSpec) error {
+	var result error
+	for index, comparisonSpec := range comparisonSpecs {
+		comparison, err := getComparison(comparisonSpec)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not get comparison %d", index))
+			continue
+		}
+		s.comparisons = append(s.comparisons, comparison)
+	}
+	return result
+}
+
+func getComparison(comparisonSpec ComparisonSpec) (*Comparison, error) {
+	if comparisonSpec.Left == nil {
+		return nil, errors.Errorf("left key is missing for comparison")
+	}
+	if comparisonSpec.Right == nil {
+		return nil, errors.Errorf("right key is missing for comparison")
+	}
+	left, err := getOperand(comparisonSpec.Left)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get left operand")
+	}
+	right, err := getOperand(comparisonSpec.Right)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get right operand")
+	}
+	comparison := &Comparison{left: left, operator: comparisonSpec.Operator, right: right}
+	err = comparison.check()
+	if err != nil {
+		return nil, err
+	}
+	return comparison, nil
+}
@@ -0,0 +1,163 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const invoiceSchema = `{
+	"fields": [
+		{"name": "start", "type": "string"},
+		{"name": "end", "type": "string"},
+		{"name": "subtotal", "type": "float"},
+		{"name": "tax", "type": "float"},
+		{"name": "total", "type": "float"},
+		{"name": "item_count", "type": "integer"},
+		{"name": "items", "type": "array", "items": {"name": "item", "type": "string"}}
+	],
+	"comparisons": [
+		{"left": "start", "operator": "less_than", "right": "end"},
+		{"left": {"sum": ["subtotal", "tax"]}, "operator": "equal", "right": "total"},
+		{"left": {"count": "items"}, "operator": "equal", "right": "item_count"}
+	]
+}`
+
+func TestSchema_Compare(t *testing.T) {
+	schema := NewSchema(
+		Integer("min"),
+		Integer("max"),
+		String("password"),
+		String("password_confirm"),
+		Float("subtotal"),
+		Float("tax"),
+		Float("total"),
+		Array("items", String("item")),
+		Integer("item_count"),
+	).
+		LessOrEqual("min", "max").
+		Equal("password", "password_confirm").
+		Compare(Sum("subtotal", "tax"), EqualOperator, FieldValue("total")).
+		Compare(Count("items"), EqualOperator, FieldValue("item_count"))
+
+	t.Run("valid", func(t *testing.T) {
+		assert.Nil(t, schema.ValidateString(`{}`))
+		assert.Nil(t, schema.ValidateString(`{"min": 1, "max": 1, "password": "a", "password_confirm": "a"}`))
+		assert.Nil(t, schema.ValidateString(`{"subtotal": 0.1, "tax": 0.2, "total": 0.3}`))
+		assert.Nil(t, schema.ValidateString(`{"items": ["a", "b"], "item_count": 2}`))
+	})
+	t.Run("missing", func(t *testing.T) {
+		assert.Nil(t, schema.ValidateString(`{"min": 2}`))
+		assert.Nil(t, schema.ValidateString(`{"subtotal": 1, "total": 3}`))
+	})
+	t.Run("less_or_equal", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"min": 2, "max": 1}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/min", errs[0].Path)
+		assert.Equal(t, []string{"/max"}, errs[0].RelatedPaths)
+		assert.Equal(t, "less_or_equal", errs[0].Rule)
+		assert.Equal(t, float64(2), errs[0].Actual)
+		assert.Equal(t, float64(1), errs[0].Expected)
+		assert.Equal(t, "Value of min should be less than or equal to value of max", errs[0].Message)
+	})
+	t.Run("equal", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"password": "a", "password_confirm": "b"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/password", errs[0].Path)
+		assert.Equal(t, []string{"/password_confirm"}, errs[0].RelatedPaths)
+	})
+	t.Run("sum", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"subtotal": 1, "tax": 0.5, "total": 1}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/subtotal", errs[0].Path)
+		assert.Equal(t, []string{"/tax", "/total"}, errs[0].RelatedPaths)
+		assert.Equal(t, "Sum of subtotal, tax should be equal to value of total", errs[0].Message)
+	})
+	t.Run("count", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"items": ["a"], "item_count": 2}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/items", errs[0].Path)
+		assert.Equal(t, "Count of items should be equal to value of item_count", errs[0].Message)
+	})
+	t.Run("invalid_fields", func(t *testing.T) {
+		// comparisons of fields which are rejected by their own rules are skipped
+		errs := schema.ValidateDetailed([]byte(`{"min": "a", "max": 1}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/min", errs[0].Path)
+		assert.Equal(t, "type", errs[0].Rule)
+	})
+	t.Run("not_comparable", func(t *testing.T) {
+		schema := NewSchema().LessThan("a", "b").Equal("c", "d")
+		errs := schema.ValidateDetailed([]byte(`{"a": 1, "b": "x", "c": [1, {"x": true}], "d": [1, {"x": true}]}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "Value of a could not be compared with value of b", errs[0].Message)
+	})
+	t.Run("large_exponent", func(t *testing.T) {
+		schema := NewSchema().LessThan("a", "b").Compare(Sum("c", "d"), EqualOperator, FieldValue("e"))
+		errs := schema.ValidateDetailed([]byte(`{"a": 1e10000000, "b": 1, "c": 1, "d": -1e-10000000, "e": 1}`))
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "/a", errs[0].Path)
+		assert.Equal(t, "less_than", errs[0].Rule)
+		assert.Equal(t, "Value of a is out of range, its exponent should be at most 1000", errs[0].Message)
+		assert.Equal(t, "/c", errs[1].Path)
+		assert.Equal(t, "Value of d is out of range, its exponent should be at most 1000", errs[1].Message)
+	})
+	t.Run("nested", func(t *testing.T) {
+		schema := NewSchema(Object("period", NewSchema(String("start"), String("end")).LessThan("start", "end")))
+		errs := schema.ValidateDetailed([]byte(`{"period": {"start": "2021-02-01", "end": "2021-01-01"}}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/period/start", errs[0].Path)
+		assert.Equal(t, []string{"/period/end"}, errs[0].RelatedPaths)
+	})
+	t.Run("compile", func(t *testing.T) {
+		_, err := schema.Compile()
+		assert.Nil(t, err)
+
+		_, err = NewSchema().Compare(Sum(), EqualOperator, FieldValue("a")).Compile()
+		assert.NotNil(t, err)
+		_, err = NewSchema().Compare(FieldValue("a"), "like", FieldValue("b")).Compile()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSchema_ComparisonsSpec(t *testing.T) {
+	schema, err := ReadFromString(invoiceSchema)
+	assert.Nil(t, err)
+
+	assert.Nil(t, schema.ValidateString(`{"start": "2021-01-01", "end": "2021-01-02", "subtotal": 10, "tax": 1.5, "total": 11.5, "items": ["a"], "item_count": 1}`))
+	errs := schema.ValidateDetailed([]byte(`{"start": "2021-01-02", "end": "2021-01-01", "subtotal": 10, "tax": 1, "total": 10, "items": [], "item_count": 1}`))
+	assert.Len(t, errs, 3)
+
+	t.Run("marshal", func(t *testing.T) {
+		content, err := json.Marshal(schema)
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `{"left":{"sum":["subtotal","tax"]},"operator":"equal","right":"total"}`)
+
+		parsed, err := ReadFromBytes(content)
+		assert.Nil(t, err)
+		assert.Equal(t, schema.comparisons, parsed.comparisons)
+
+		_, err = json.Marshal(Operand{})
+		assert.NotNil(t, err)
+		_, err = json.Marshal(Sum())
+		assert.NotNil(t, err)
+	})
+	t.Run("json_schema", func(t *testing.T) {
+		_, err := schema.ToJSONSchema()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "comparisons can not be represented")
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := ReadFromString(`{"fields": [], "comparisons": [{"left": "a", "operator": "like", "right": "b"}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "invalid comparison operator: like")
+
+		_, err = ReadFromString(`{"fields": [], "comparisons": [{"left": "a", "operator": "equal"}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "right key is missing")
+
+		_, err = ReadFromString(`{"fields": [], "comparisons": [{"left": {"max": ["a"]}, "operator": "equal", "right": "b"}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "could not get left operand")
+	})
+}
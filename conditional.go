@@ -0,0 +1,225 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"sort"
+)
+
+// Conditional is a rule of a schema which validates a json object with a schema which depends on the object itself.
+// if the object is valid according to the If schema, it is validated with the Then schema, otherwise with the Else
+// schema. since the schemas see the whole object, a conditional can make a field required, or change its constraints,
+// based on the values of its siblings.
+type Conditional struct {
+	condition Schema
+	then      *Schema
+	otherwise *Schema
+}
+
+// If is the constructor of a Conditional. condition is a schema which the object should be valid against, e.g.
+// NewSchema(String("payment_method").Choices("card").Required()) matches objects which are paid with a card.
+func If(condition Schema) *Conditional {
+	return &Conditional{
+		condition: condition,
+	}
+}
+
+// Then sets the schema which validates objects which match the condition.
+func (c *Conditional) Then(schema Schema) *Conditional {
+	c.then = &schema
+	return c
+}
+
+// Else sets the schema which validates objects which do not match the condition.
+func (c *Conditional) Else(schema Schema) *Conditional {
+	c.otherwise = &schema
+	return c
+}
+
+// validate validates a json object with the Then or Else schema of the conditional.
+func (c *Conditional) validate(json gjson.Result) error {
+	branch := c.otherwise
+	if c.condition.validateJSON(json) == nil {
+		branch = c.then
+	}
+	if branch == nil {
+		return nil
+	}
+	return branch.validateJSON(json)
+}
+
+// schemas returns the schemas of the conditional which are set.
+func (c *Conditional) schemas() []*Schema {
+	schemas := []*Schema{&c.condition}
+	if c.then != nil {
+		schemas = append(schemas, c.then)
+	}
+	if c.otherwise != nil {
+		schemas = append(schemas, c.otherwise)
+	}
+	return schemas
+}
+
+func (c *Conditional) MarshalJSON() ([]byte, error) {
+	return json.Marshal(conditionalJSON{
+		If:   c.condition,
+		Then: c.then,
+		Else: c.otherwise,
+	})
+}
+
+type conditionalJSON struct {
+	If   Schema  `json:"if"`
+	Then *Schema `json:"then,omitempty"`
+	Else *Schema `json:"else,omitempty"`
+}
+
+// ConditionalSpec is used for parsing a Conditional. If, Then and Else are schema specs.
+type ConditionalSpec struct {
+	If   map[string]interface{} `json:"if"`
+	Then map[string]interface{} `json:"then,omitempty"`
+	Else map[string]interface{} `json:"else,omitempty"`
+}
+
+// dependency makes fields of a json object required if another field is present.
+type dependency struct {
+	field    string
+	required []string
+}
+
+// When adds conditional rules to the schema. e.g.
+// schema.When(If(NewSchema(String("payment_method").Choices("card").Required())).
+// Then(NewSchema(String("card_number").Required()))) requires a card number if the payment method is card.
+// fields which are only declared in Then and Else schemas are not additional properties of the schema.
+func (s Schema) When(conditionals ...*Conditional) Schema {
+	s.conditionals = append(append([]*Conditional{}, s.conditionals...), conditionals...)
+	return s
+}
+
+// DependentRequired makes the given fields required if field is present in a json object, even if its value is null.
+// e.g. schema.DependentRequired("start_date", "end_date") requires end_date if start_date is present.
+func (s Schema) DependentRequired(field string, required ...string) Schema {
+	s.dependencies = append(append([]dependency{}, s.dependencies...), dependency{field: field, required: required})
+	return s
+}
+
+// validateRules validates a json object with conditional rules and dependencies of the schema.
+func (s *Schema) validateRules(json gjson.Result) error {
+	var result error
+	for _, conditional := range s.conditionals {
+		err := conditional.validate(json)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	if len(s.dependencies) > 0 && json.IsObject() {
+		present := make(map[string]struct{})
+		json.ForEach(func(key, _ gjson.Result) bool {
+			present[key.String()] = struct{}{}
+			return true
+		})
+		err := s.validateDependencies(present)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// validateDependencies validates the dependencies of the schema with the names of the present properties of an object.
+func (s *Schema) validateDependencies(present map[string]struct{}) error {
+	var result error
+	for _, dependency := range s.dependencies {
+		if _, found := present[dependency.field]; !found {
+			continue
+		}
+		for _, name := range dependency.required {
+			if _, found := present[name]; found {
+				continue
+			}
+			err := newValidationError(name, requiredRule, true, nil, "Value for %s field is required when %s is present", name, dependency.field)
+			result = multierror.Append(result, prefixPath(err, name))
+		}
+	}
+	return result
+}
+
+// conditionalFieldNames returns the names of fields which are declared in Then and Else schemas of the conditionals.
+func (s *Schema) conditionalFieldNames() []string {
+	var names []string
+	for _, conditional := range s.conditionals {
+		for _, schema := range conditional.schemas()[1:] {
+			for _, field := range schema.Fields {
+				names = append(names, field.GetName())
+			}
+		}
+	}
+	return names
+}
+
+// dependenciesSpec returns the value of dependent_required key of the schema spec.
+func (s *Schema) dependenciesSpec() map[string][]string {
+	if len(s.dependencies) == 0 {
+		return nil
+	}
+	spec := make(map[string][]string, len(s.dependencies))
+	for _, dependency := range s.dependencies {
+		spec[dependency.field] = append(spec[dependency.field], dependency.required...)
+	}
+	return spec
+}
+
+// setRulesSpec parses the conditions and dependent_required keys of a schema spec.
+func (s *Schema) setRulesSpec(conditionalSpecs []ConditionalSpec, dependenciesSpec map[string][]string) error {
+	var result error
+	for index, conditionalSpec := range conditionalSpecs {
+		conditional, err := s.getConditional(conditionalSpec)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not get condition %d", index))
+			continue
+		}
+		s.conditionals = append(s.conditionals, conditional)
+	}
+
+	fields := make([]string, 0, len(dependenciesSpec))
+	for field := range dependenciesSpec {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		s.dependencies = append(s.dependencies, dependency{field: field, required: dependenciesSpec[field]})
+	}
+	return result
+}
+
+func (s *Schema) getConditional(conditionalSpec ConditionalSpec) (*Conditional, error) {
+	if conditionalSpec.If == nil {
+		return nil, errors.Errorf("if key is missing for condition")
+	}
+	if conditionalSpec.Then == nil && conditionalSpec.Else == nil {
+		return nil, errors.Errorf("then or else key is required for condition")
+	}
+
+	condition, err := s.getSchema(conditionalSpec.If)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get if schema")
+	}
+	conditional := If(condition)
+	if conditionalSpec.Then != nil {
+		then, err := s.getSchema(conditionalSpec.Then)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get then schema")
+		}
+		conditional.Then(then)
+	}
+	if conditionalSpec.Else != nil {
+		otherwise, err := s.getSchema(conditionalSpec.Else)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get else schema")
+		}
+		conditional.Else(otherwise)
+	}
+	return conditional, nil
+}
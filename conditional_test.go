@@ -0,0 +1,154 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const paymentSchema = `{
+	"fields": [
+		{"name": "payment_method", "type": "string", "choices": ["card", "cash"], "required": true},
+		{"name": "start_date", "type": "string"}
+	],
+	"conditions": [{
+		"if": {"fields": [{"name": "payment_method", "type": "string", "choices": ["card"], "required": true}]},
+		"then": {"fields": [{"name": "card_number", "type": "string", "min_length": 12, "required": true}]},
+		"else": {"fields": [{"name": "card_number", "type": "null"}]}
+	}],
+	"dependent_required": {"start_date": ["end_date"]},
+	"additional_properties": false
+}`
+
+func TestSchema_When(t *testing.T) {
+	schema := NewSchema(
+		String("payment_method").Choices("card", "cash").Required(),
+		Integer("amount"),
+	).When(
+		If(NewSchema(String("payment_method").Choices("card").Required())).
+			Then(NewSchema(String("card_number").MinLength(12).Required())),
+		If(NewSchema(Integer("amount").Min(1000).Required())).
+			Then(NewSchema(String("approver").Required())).
+			Else(NewSchema(String("approver").MaxLength(0))),
+	)
+
+	t.Run("valid", func(t *testing.T) {
+		assert.Nil(t, schema.ValidateString(`{"payment_method": "cash", "amount": 10}`))
+		assert.Nil(t, schema.ValidateString(`{"payment_method": "card", "card_number": "123456789012"}`))
+		assert.Nil(t, schema.ValidateString(`{"payment_method": "cash", "amount": 2000, "approver": "bob"}`))
+	})
+	t.Run("then", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"payment_method": "card"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/card_number", errs[0].Path)
+		assert.Equal(t, "required", errs[0].Rule)
+
+		errs = schema.ValidateDetailed([]byte(`{"payment_method": "card", "card_number": "1234"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "min_length", errs[0].Rule)
+	})
+	t.Run("else", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"payment_method": "cash", "amount": 10, "approver": "bob"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/approver", errs[0].Path)
+		assert.Equal(t, "max_length", errs[0].Rule)
+	})
+	t.Run("condition_errors", func(t *testing.T) {
+		// errors of the if schema only select the branch, and are not reported
+		errs := schema.ValidateDetailed([]byte(`{"payment_method": "cash", "amount": "ten"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/amount", errs[0].Path)
+	})
+	t.Run("immutable", func(t *testing.T) {
+		base := NewSchema(String("name"))
+		_ = base.When(If(NewSchema(String("name").Required())).Then(NewSchema(String("id").Required())))
+		assert.Nil(t, base.ValidateString(`{"name": "a"}`))
+	})
+	t.Run("compiled", func(t *testing.T) {
+		validator, err := schema.Compile()
+		assert.Nil(t, err)
+		assert.Nil(t, validator.ValidateString(`{"payment_method": "card", "card_number": "123456789012"}`))
+		assert.NotNil(t, validator.ValidateString(`{"payment_method": "card"}`))
+	})
+	t.Run("strict", func(t *testing.T) {
+		strict := schema.Strict()
+		assert.Nil(t, strict.ValidateString(`{"payment_method": "card", "card_number": "123456789012"}`))
+		assert.NotNil(t, strict.ValidateString(`{"payment_method": "card", "card_number": "123456789012", "cvv": 1}`))
+	})
+}
+
+func TestSchema_DependentRequired(t *testing.T) {
+	schema := NewSchema(String("start_date").Nullable(), String("end_date")).
+		DependentRequired("start_date", "end_date")
+
+	assert.Nil(t, schema.ValidateString(`{}`))
+	assert.Nil(t, schema.ValidateString(`{"end_date": "2021-01-02"}`))
+	assert.Nil(t, schema.ValidateString(`{"start_date": "2021-01-01", "end_date": "2021-01-02"}`))
+
+	errs := schema.ValidateDetailed([]byte(`{"start_date": null}`))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/end_date", errs[0].Path)
+	assert.Equal(t, "required", errs[0].Rule)
+	assert.Equal(t, "Value for end_date field is required when start_date is present", errs[0].Message)
+}
+
+func TestSchema_ConditionsSpec(t *testing.T) {
+	schema, err := ReadFromString(paymentSchema)
+	assert.Nil(t, err)
+
+	assert.Nil(t, schema.ValidateString(`{"payment_method": "cash"}`))
+	assert.Nil(t, schema.ValidateString(`{"payment_method": "card", "card_number": "123456789012"}`))
+	assert.NotNil(t, schema.ValidateString(`{"payment_method": "card"}`))
+	assert.NotNil(t, schema.ValidateString(`{"payment_method": "cash", "card_number": "123456789012"}`))
+	assert.NotNil(t, schema.ValidateString(`{"payment_method": "cash", "start_date": "2021-01-01"}`))
+
+	t.Run("marshal", func(t *testing.T) {
+		content, err := json.Marshal(schema)
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `"dependent_required":{"start_date":["end_date"]}`)
+
+		parsed, err := ReadFromBytes(content)
+		assert.Nil(t, err)
+		assert.Nil(t, parsed.ValidateString(`{"payment_method": "card", "card_number": "123456789012"}`))
+		assert.NotNil(t, parsed.ValidateString(`{"payment_method": "card"}`))
+	})
+	t.Run("json_schema", func(t *testing.T) {
+		document, err := schema.ToJSONSchema()
+		assert.Nil(t, err)
+		assert.Contains(t, string(document), `"dependentRequired":{"start_date":["end_date"]}`)
+
+		imported, err := FromJSONSchema(document)
+		assert.Nil(t, err)
+		assert.Nil(t, imported.ValidateString(`{"payment_method": "card", "card_number": "123456789012"}`))
+		assert.NotNil(t, imported.ValidateString(`{"payment_method": "card"}`))
+		assert.NotNil(t, imported.ValidateString(`{"payment_method": "cash", "start_date": "2021-01-01"}`))
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := ReadFromString(`{"fields": [], "conditions": [{"then": {"fields": []}}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "if key is missing")
+
+		_, err = ReadFromString(`{"fields": [], "conditions": [{"if": {"fields": []}}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "then or else key is required")
+
+		_, err = ReadFromString(`{"fields": [], "conditions": [{"if": {"fields": [{"name": "a", "type": "date"}]}, "then": {"fields": []}}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "could not get condition 0")
+	})
+	t.Run("yaml_position", func(t *testing.T) {
+		_, err := ReadFromYAML([]byte(`fields: []
+conditions:
+  - if:
+      fields: []
+    then:
+      fields:
+        - name: card_number
+          type: date
+`))
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/conditions/0/then/fields/0", errs[0].Path)
+		assert.Equal(t, 7, errs[0].Line)
+	})
+}
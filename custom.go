@@ -0,0 +1,106 @@
+package vjson
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+// ValidatorFunc is a custom validation rule of a field, e.g. a Luhn check of a card number.
+// it receives the value of the field after the other rules of the field accept it, and returns an error if the value
+// is invalid. the value is a Go value of the json, e.g. float64 for numbers and map[string]interface{} for objects.
+type ValidatorFunc func(value interface{}) error
+
+// customValidator is a validator function of a field, which is added with Custom or by name in validators key of a
+// field spec. validators without a function use the function which is registered with their name when they are called,
+// so schemas can be parsed before their validators are registered.
+type customValidator struct {
+	name string
+	fn   ValidatorFunc
+}
+
+// function returns the function of the validator, or the function which is registered with its name.
+func (c customValidator) function() (ValidatorFunc, bool) {
+	if c.fn != nil {
+		return c.fn, true
+	}
+	return lookupValidator(c.name)
+}
+
+type customValidators []customValidator
+
+// customValidated is implemented by fields which have custom validators.
+type customValidated interface {
+	getValidators() customValidators
+}
+
+// namedValidators returns validators of the given names, which use registered functions.
+func namedValidators(names []string) customValidators {
+	if len(names) == 0 {
+		return nil
+	}
+	validators := make(customValidators, 0, len(names))
+	for _, name := range names {
+		validators = append(validators, customValidator{name: name})
+	}
+	return validators
+}
+
+// add returns the validators with a new validator.
+func (c customValidators) add(name string, fn ValidatorFunc) customValidators {
+	return append(c, customValidator{name: name, fn: fn})
+}
+
+// names returns the names of the validators, for validators key of a field spec.
+func (c customValidators) names() []string {
+	if len(c) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(c))
+	for _, validator := range c {
+		names = append(names, validator.name)
+	}
+	return names
+}
+
+// check returns an error if a validator has no function and no function is registered with its name.
+func (c customValidators) check() error {
+	var result error
+	for _, validator := range c {
+		if _, found := validator.function(); !found {
+			result = multierror.Append(result, errors.Errorf("validator %s is not registered", validator.name))
+		}
+	}
+	return result
+}
+
+// validate calls the validators with a value of a field. errors which are not a ValidationError are reported with
+// the name of the validator as their rule.
+func (c customValidators) validate(field string, value interface{}) error {
+	var result error
+	for _, validator := range c {
+		fn, found := validator.function()
+		if !found {
+			result = multierror.Append(result, newValidationError(field, validator.name, nil, value, "Value for %s could not be validated: validator %s is not registered", field, validator.name))
+			continue
+		}
+		err := fn(value)
+		if err == nil {
+			continue
+		}
+		var validationError *ValidationError
+		if errors.As(err, &validationError) {
+			result = multierror.Append(result, validationError)
+			continue
+		}
+		result = multierror.Append(result, newValidationError(field, validator.name, nil, value, "Value for %s field is invalid according to %s: %v", field, validator.name, err))
+	}
+	return result
+}
+
+// checkValidators returns an error if a custom validator of the field is not registered.
+func checkValidators(field Field) error {
+	if custom, ok := field.(customValidated); ok {
+		return custom.getValidators().check()
+	}
+	return nil
+}
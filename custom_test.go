@@ -0,0 +1,150 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// luhn is a custom validator of the tests, which checks the Luhn checksum of a card number.
+func luhn(value interface{}) error {
+	number, _ := value.(string)
+	sum := 0
+	for index := range number {
+		digit := int(number[len(number)-1-index] - '0')
+		if digit < 0 || digit > 9 {
+			return errors.Errorf("%s is not a number", number)
+		}
+		if index%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return errors.Errorf("checksum of %s is invalid", number)
+	}
+	return nil
+}
+
+func init() {
+	RegisterValidator("luhn", luhn)
+}
+
+func TestField_Custom(t *testing.T) {
+	even := func(value interface{}) error {
+		if int(value.(float64))%2 != 0 {
+			return errors.New("odd number")
+		}
+		return nil
+	}
+	unique := func(value interface{}) error {
+		seen := make(map[interface{}]bool)
+		for _, item := range value.([]interface{}) {
+			if seen[item] {
+				return newValidationError("tags", "unique", true, item, "Value %v is repeated", item)
+			}
+			seen[item] = true
+		}
+		return nil
+	}
+	schema := NewSchema(
+		String("card").MinLength(4).Custom("luhn", luhn),
+		Integer("count").Nullable().Custom("even", even),
+		Array("tags", String("tag")).Custom("unique", unique),
+		Object("address", NewSchema(String("city"))).Custom("city", func(value interface{}) error {
+			if _, found := value.(map[string]interface{})["city"]; !found {
+				return errors.New("city is missing")
+			}
+			return nil
+		}),
+	)
+
+	t.Run("valid", func(t *testing.T) {
+		assert.Nil(t, schema.ValidateString(`{"card": "4111111111111111", "count": 2, "tags": ["a", "b"], "address": {"city": "Paris"}}`))
+		assert.Nil(t, schema.ValidateString(`{"count": null}`))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"card": "4111111111111112", "count": 3, "tags": ["a", "a"], "address": {}}`))
+		assert.Len(t, errs, 4)
+		assert.Equal(t, "/card", errs[0].Path)
+		assert.Equal(t, "luhn", errs[0].Rule)
+		assert.Equal(t, "Value for card field is invalid according to luhn: checksum of 4111111111111112 is invalid", errs[0].Message)
+		assert.Equal(t, "/count", errs[1].Path)
+		assert.Equal(t, "even", errs[1].Rule)
+		assert.Equal(t, "/tags", errs[2].Path)
+		assert.Equal(t, "unique", errs[2].Rule)
+		assert.Equal(t, "Value a is repeated", errs[2].Message)
+		assert.Equal(t, "/address", errs[3].Path)
+	})
+	t.Run("other_rules_first", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"card": "123"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "min_length", errs[0].Rule)
+	})
+	t.Run("go_values", func(t *testing.T) {
+		field := Array("cards", String("card").Custom("luhn", luhn))
+		assert.Nil(t, field.Validate([]interface{}{"4111111111111111"}))
+		assert.NotNil(t, field.Validate([]interface{}{"4111111111111112"}))
+	})
+	t.Run("definition", func(t *testing.T) {
+		schema := NewSchema(Ref("card", "card").Required()).Define("card", String("card").Custom("luhn", luhn))
+		assert.Nil(t, schema.ValidateString(`{"card": "4111111111111111"}`))
+		assert.NotNil(t, schema.ValidateString(`{"card": "4111111111111112"}`))
+	})
+	t.Run("combinator", func(t *testing.T) {
+		field := OneOf("id", String("id"), Integer("id")).Custom("even", func(value interface{}) error {
+			if number, ok := value.(float64); ok {
+				return even(number)
+			}
+			return nil
+		})
+		schema := NewSchema(field)
+		assert.Nil(t, schema.ValidateString(`{"id": 2}`))
+		assert.NotNil(t, schema.ValidateString(`{"id": 3}`))
+	})
+}
+
+func TestSchema_Validators(t *testing.T) {
+	schema, err := ReadFromString(`{"fields": [{"name": "card", "type": "string", "required": true, "validators": ["luhn"]}]}`)
+	assert.Nil(t, err)
+	assert.Nil(t, schema.ValidateString(`{"card": "4111111111111111"}`))
+	errs := schema.ValidateDetailed([]byte(`{"card": "4111111111111112"}`))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/card", errs[0].Path)
+	assert.Equal(t, "luhn", errs[0].Rule)
+
+	t.Run("marshal", func(t *testing.T) {
+		content, err := json.Marshal(NewSchema(Integer("count").Custom("luhn", nil)))
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `"validators":["luhn"]`)
+
+		parsed, err := ReadFromBytes(content)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"luhn"}, parsed.Fields[0].(*IntegerField).validators.names())
+	})
+	t.Run("not_registered", func(t *testing.T) {
+		schema, err := ReadFromString(`{"fields": [{"name": "iban", "type": "string", "validators": ["iban"]}]}`)
+		assert.Nil(t, err)
+
+		_, err = schema.Compile()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "validator iban is not registered")
+
+		errs := schema.ValidateDetailed([]byte(`{"iban": "x"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "Value for iban could not be validated: validator iban is not registered", errs[0].Message)
+
+		RegisterValidator("iban", func(interface{}) error { return nil })
+		assert.Nil(t, schema.ValidateString(`{"iban": "x"}`))
+		_, err = schema.Compile()
+		assert.Nil(t, err)
+	})
+	t.Run("invalid_registration", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterValidator("", luhn) })
+		assert.Panics(t, func() { RegisterValidator("luhn", nil) })
+	})
+}
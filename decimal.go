@@ -0,0 +1,388 @@
+package vjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// decimalPattern is the syntax of json numbers, which is used for decimal numbers and numeric strings.
+var decimalPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// decimal is a parsed decimal number.
+type decimal struct {
+	value *big.Rat
+	// precision is the number of significant digits, and scale is the number of digits after the decimal point.
+	// trailing zeros of the fraction are not counted, so 19.990 has a precision of 4 and a scale of 2.
+	precision     int
+	scale         int
+	integerDigits int
+}
+
+// parseDecimal parses the text of a decimal number exactly. numbers with exponents larger than maxExponent are
+// not parsed.
+func parseDecimal(text string) (decimal, bool) {
+	match := decimalPattern.FindStringSubmatch(text)
+	if match == nil {
+		return decimal{}, false
+	}
+	exponent := 0
+	if match[3] != "" {
+		value, err := strconv.Atoi(match[3][1:])
+		if err != nil || value > maxExponent || value < -maxExponent {
+			return decimal{}, false
+		}
+		exponent = value
+	}
+	fraction := strings.TrimPrefix(match[2], ".")
+	digits := strings.TrimLeft(match[1]+fraction, "0")
+	exponent -= len(fraction)
+	significant := strings.TrimRight(digits, "0")
+	exponent += len(digits) - len(significant)
+
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return decimal{}, false
+	}
+	if significant == "" {
+		// zero has a single digit and no decimal places, however it is written, e.g. 0.00000 or 0e5
+		return decimal{value: value, precision: 1}, true
+	}
+
+	scale := 0
+	if exponent < 0 {
+		scale = -exponent
+	}
+	integerDigits := len(significant) + exponent
+	if integerDigits < 0 {
+		integerDigits = 0
+	}
+	return decimal{value: value, precision: integerDigits + scale, scale: scale, integerDigits: integerDigits}, true
+}
+
+// parseBound parses a bound of a decimal field. it returns nil if the bound is not a decimal number, which is
+// reported by Compile.
+func parseBound(text string) *big.Rat {
+	number, ok := parseDecimal(text)
+	if !ok {
+		return nil
+	}
+	return number.value
+}
+
+type decimalRange struct {
+	start      string
+	startValue *big.Rat
+	end        string
+	endValue   *big.Rat
+}
+
+// DecimalField is the type for validating decimal numbers in a JSON, like monetary amounts.
+// the raw text of json numbers is validated, so values and bounds are compared exactly.
+type DecimalField struct {
+	name     string
+	required bool
+	nullable bool
+
+	precision           int
+	precisionValidation bool
+
+	scale           int
+	scaleValidation bool
+
+	min           string
+	minValue      *big.Rat
+	minValidation bool
+
+	max           string
+	maxValue      *big.Rat
+	maxValidation bool
+
+	rangeValidation bool
+	ranges          []decimalRange
+
+	allowStrings bool
+
+	validators   customValidators
+	defaultValue interface{}
+}
+
+// To Force Implementing Field interface by DecimalField
+var _ Field = (*DecimalField)(nil)
+
+// GetName returns name of the field
+func (d *DecimalField) GetName() string {
+	return d.name
+}
+
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+// json numbers are passed to the function as json.Number, so they do not lose precision.
+func (d *DecimalField) Custom(name string, fn ValidatorFunc) *DecimalField {
+	d.validators = d.validators.add(name, fn)
+	return d
+}
+
+func (d *DecimalField) getValidators() customValidators {
+	return d.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+// the value is written as a json number, e.g. "0.00".
+func (d *DecimalField) Default(value string) *DecimalField {
+	d.defaultValue = json.Number(value)
+	return d
+}
+
+func (d *DecimalField) getDefault() (interface{}, bool) {
+	return d.defaultValue, d.defaultValue != nil
+}
+
+// Validate is used for validating a value. it returns an error if the value is invalid.
+func (d *DecimalField) Validate(v interface{}) error {
+	err := d.validateValue(v)
+	if err != nil || v == nil {
+		return err
+	}
+	return d.validators.validate(d.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (d *DecimalField) validateValue(v interface{}) error {
+	if v == nil {
+		if !d.required {
+			return nil
+		}
+		return requiredError(d.name)
+	}
+
+	text, ok := d.text(v)
+	var number decimal
+	if ok {
+		number, ok = parseDecimal(text)
+	}
+	if !ok {
+		if d.allowStrings {
+			return newValidationError(d.name, typeRule, decimalType, v, "Value for %s should be a decimal number or a numeric string", d.name)
+		}
+		return newValidationError(d.name, typeRule, decimalType, v, "Value for %s should be a decimal number", d.name)
+	}
+
+	var result error
+	digits := number.precision
+	if d.scaleValidation && number.scale <= d.scale {
+		// like a NUMERIC(precision, scale) column, the fraction is padded to the scale of the field
+		digits = number.integerDigits + d.scale
+	}
+	if d.precisionValidation && digits > d.precision {
+		result = multierror.Append(result, newValidationError(d.name, "precision", d.precision, v, "Value for %s should have at most %d digits", d.name, d.precision))
+	}
+
+	if d.scaleValidation && number.scale > d.scale {
+		result = multierror.Append(result, newValidationError(d.name, "scale", d.scale, v, "Value for %s should have at most %d decimal places", d.name, d.scale))
+	}
+
+	if d.minValidation && d.minValue != nil {
+		if number.value.Cmp(d.minValue) < 0 {
+			result = multierror.Append(result, newValidationError(d.name, "min", d.min, v, "Value for %s should be at least %s", d.name, d.min))
+		}
+	}
+
+	if d.maxValidation && d.maxValue != nil {
+		if number.value.Cmp(d.maxValue) > 0 {
+			result = multierror.Append(result, newValidationError(d.name, "max", d.max, v, "Value for %s should be at most %s", d.name, d.max))
+		}
+	}
+
+	if d.rangeValidation {
+		inRange := false
+		for _, r := range d.ranges {
+			if r.startValue != nil && r.endValue != nil && number.value.Cmp(r.startValue) >= 0 && number.value.Cmp(r.endValue) <= 0 {
+				inRange = true
+				break
+			}
+		}
+
+		if !inRange {
+			var ranges strings.Builder
+			for _, r := range d.ranges {
+				ranges.WriteString(fmt.Sprintf("[%s,%s] ", r.start, r.end))
+			}
+			result = multierror.Append(result, newValidationError(d.name, "ranges", d.rangeSpecs(), v, "Value for %s should be in one of these ranges: %s", d.name, ranges.String()))
+		}
+	}
+
+	return result
+}
+
+// text returns the text of a decimal value. strings are only accepted if the field allows them.
+func (d *DecimalField) text(v interface{}) (string, bool) {
+	switch value := v.(type) {
+	case json.Number:
+		return string(value), true
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64), true
+	case string:
+		return value, d.allowStrings
+	}
+	if number, ok := toInt64(v); ok {
+		return strconv.FormatInt(number, 10), true
+	}
+	if number, ok := toUint64(v); ok {
+		return strconv.FormatUint(number, 10), true
+	}
+	return "", false
+}
+
+// validateResult validates the raw text of a json number, so it does not lose precision in float64.
+func (d *DecimalField) validateResult(value gjson.Result) error {
+	return d.validateValue(d.resultValue(value))
+}
+
+// resultValue returns the value of a json value which is validated by the field. numbers are returned as
+// json.Number with their raw text.
+func (d *DecimalField) resultValue(value gjson.Result) interface{} {
+	if value.Type == gjson.Number {
+		return json.Number(strings.TrimSpace(value.Raw))
+	}
+	return value.Value()
+}
+
+func (d *DecimalField) compile() error {
+	var result error
+	if d.precisionValidation && d.precision < 1 {
+		result = multierror.Append(result, errors.Errorf("precision of decimal field %s should be positive", d.name))
+	}
+	if d.scaleValidation && d.scale < 0 {
+		result = multierror.Append(result, errors.Errorf("scale of decimal field %s should not be negative", d.name))
+	}
+	if d.precisionValidation && d.scaleValidation && d.scale > d.precision {
+		result = multierror.Append(result, errors.Errorf("scale of decimal field %s should not be greater than its precision", d.name))
+	}
+	if d.minValidation && d.minValue == nil {
+		result = multierror.Append(result, errors.Errorf("min %s of decimal field %s is not a decimal number", d.min, d.name))
+	}
+	if d.maxValidation && d.maxValue == nil {
+		result = multierror.Append(result, errors.Errorf("max %s of decimal field %s is not a decimal number", d.max, d.name))
+	}
+	for _, r := range d.ranges {
+		if r.startValue == nil || r.endValue == nil {
+			result = multierror.Append(result, errors.Errorf("range [%s,%s] of decimal field %s is not a range of decimal numbers", r.start, r.end, d.name))
+		}
+	}
+	return result
+}
+
+// Required is called to make a field required in a JSON
+func (d *DecimalField) Required() *DecimalField {
+	d.required = true
+	return d
+}
+
+// Nullable is called to accept null as a valid value of the field in a JSON
+func (d *DecimalField) Nullable() *DecimalField {
+	d.nullable = true
+	return d
+}
+
+func (d *DecimalField) isNullable() bool {
+	return d.nullable
+}
+
+// Precision is called when we want to set the max number of significant digits of a decimal value in validation.
+// if a scale is set too, values should fit in a NUMERIC(precision, scale) column, so they have at most
+// precision - scale digits before the decimal point.
+func (d *DecimalField) Precision(digits int) *DecimalField {
+	d.precision = digits
+	d.precisionValidation = true
+	return d
+}
+
+// Scale is called when we want to set the max number of digits after the decimal point of a decimal value in
+// validation, e.g. 2 for amounts in cents.
+func (d *DecimalField) Scale(digits int) *DecimalField {
+	d.scale = digits
+	d.scaleValidation = true
+	return d
+}
+
+// Min is called when we want to set a minimum value for a decimal value in validation, e.g. "0.01".
+func (d *DecimalField) Min(value string) *DecimalField {
+	d.min = value
+	d.minValue = parseBound(value)
+	d.minValidation = true
+	return d
+}
+
+// Max is called when we want to set a maximum value for a decimal value in validation, e.g. "999.99".
+func (d *DecimalField) Max(value string) *DecimalField {
+	d.max = value
+	d.maxValue = parseBound(value)
+	d.maxValidation = true
+	return d
+}
+
+// Range is called when we want to define valid ranges for a decimal value in validation.
+func (d *DecimalField) Range(start, end string) *DecimalField {
+	d.ranges = append(d.ranges, decimalRange{start: start, startValue: parseBound(start), end: end, endValue: parseBound(end)})
+	d.rangeValidation = true
+	return d
+}
+
+// AllowStrings is called when we want to accept numeric strings like "19.99" as decimal values in validation.
+func (d *DecimalField) AllowStrings() *DecimalField {
+	d.allowStrings = true
+	return d
+}
+
+func (d *DecimalField) rangeSpecs() []DecimalRangeSpec {
+	ranges := make([]DecimalRangeSpec, 0, len(d.ranges))
+	for _, r := range d.ranges {
+		ranges = append(ranges, DecimalRangeSpec{
+			Start: r.start,
+			End:   r.end,
+		})
+	}
+	return ranges
+}
+
+func (d *DecimalField) MarshalJSON() ([]byte, error) {
+	spec := DecimalFieldSpec{
+		Name:         d.name,
+		Type:         decimalType,
+		Required:     d.required,
+		Nullable:     d.nullable,
+		Min:          d.min,
+		Max:          d.max,
+		Ranges:       d.rangeSpecs(),
+		AllowStrings: d.allowStrings,
+		Validators:   d.validators.names(),
+		Default:      d.defaultValue,
+	}
+	if d.precisionValidation {
+		spec.Precision = &d.precision
+	}
+	if d.scaleValidation {
+		spec.Scale = &d.scale
+	}
+	return json.Marshal(spec)
+}
+
+// Decimal is the constructor of a decimal field
+func Decimal(name string) *DecimalField {
+	return &DecimalField{
+		name:   name,
+		ranges: []decimalRange{},
+	}
+}
@@ -0,0 +1,88 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/mitchellh/mapstructure"
+	"reflect"
+	"strconv"
+)
+
+// DecimalRangeSpec is a type for parsing a decimal field range
+type DecimalRangeSpec struct {
+	Start string `mapstructure:"start" json:"start"`
+	End   string `mapstructure:"end" json:"end"`
+}
+
+// DecimalFieldSpec is a type used for parsing a DecimalField. bounds are decimal numbers, and they may be written as
+// json numbers or strings, e.g. "0.01".
+type DecimalFieldSpec struct {
+	Name         string             `mapstructure:"name" json:"name"`
+	Type         fieldType          `json:"type"`
+	Required     bool               `mapstructure:"required" json:"required,omitempty"`
+	Nullable     bool               `mapstructure:"nullable" json:"nullable,omitempty"`
+	Precision    *int               `mapstructure:"precision" json:"precision,omitempty"`
+	Scale        *int               `mapstructure:"scale" json:"scale,omitempty"`
+	Min          string             `mapstructure:"min" json:"min,omitempty"`
+	Max          string             `mapstructure:"max" json:"max,omitempty"`
+	Ranges       []DecimalRangeSpec `mapstructure:"ranges" json:"ranges,omitempty"`
+	AllowStrings bool               `mapstructure:"allow_strings" json:"allow_strings,omitempty"`
+	Validators   []string           `mapstructure:"validators" json:"validators,omitempty"`
+	Default      interface{}        `mapstructure:"default" json:"default,omitempty"`
+}
+
+// NewDecimal receives a DecimalFieldSpec and returns a DecimalField
+func NewDecimal(spec DecimalFieldSpec, minValidation, maxValidation, rangeValidation bool) *DecimalField {
+	field := Decimal(spec.Name)
+	field.required = spec.Required
+	field.nullable = spec.Nullable
+	if spec.Precision != nil {
+		field.Precision(*spec.Precision)
+	}
+	if spec.Scale != nil {
+		field.Scale(*spec.Scale)
+	}
+	if minValidation {
+		field.Min(spec.Min)
+	}
+	if maxValidation {
+		field.Max(spec.Max)
+	}
+	for _, rangeSpec := range spec.Ranges {
+		field.Range(rangeSpec.Start, rangeSpec.End)
+	}
+	field.rangeValidation = rangeValidation
+	field.allowStrings = spec.AllowStrings
+	field.validators = namedValidators(spec.Validators)
+	field.defaultValue = decimalDefault(spec.Default)
+	return field
+}
+
+// decodeDecimalSpec decodes a decimal field spec. numbers are converted to their text for the bounds of the field.
+func decodeDecimalSpec(fieldSpec map[string]interface{}, spec *DecimalFieldSpec) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: decimalTextHook,
+		Result:     spec,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(fieldSpec)
+}
+
+func decimalTextHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if number, ok := data.(float64); ok && to.Kind() == reflect.String {
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	}
+	return data, nil
+}
+
+// decimalDefault converts the default value of a decimal field spec to json.Number.
+func decimalDefault(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+	case string:
+		return json.Number(v)
+	}
+	return value
+}
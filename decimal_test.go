@@ -0,0 +1,266 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestDecimalField_GetName(t *testing.T) {
+	field := Decimal("foo")
+	assert.Equal(t, "foo", field.GetName())
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		text          string
+		precision     int
+		scale         int
+		integerDigits int
+	}{
+		{"0", 1, 0, 0},
+		{"-0", 1, 0, 0},
+		{"0.00000", 1, 0, 0},
+		{"0e5", 1, 0, 0},
+		{"0.0e-5", 1, 0, 0},
+		{"19.990", 4, 2, 2},
+		{"100", 3, 0, 3},
+		{"100.00", 3, 0, 3},
+		{"0.05", 2, 2, 0},
+		{"1e3", 4, 0, 4},
+		{"1.2345e2", 5, 2, 3},
+		{"12e-4", 4, 4, 0},
+		{"1.50E+1", 2, 0, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			number, ok := parseDecimal(test.text)
+			assert.True(t, ok)
+			assert.Equal(t, test.precision, number.precision)
+			assert.Equal(t, test.scale, number.scale)
+			assert.Equal(t, test.integerDigits, number.integerDigits)
+		})
+	}
+}
+
+func TestDecimalField_Validate(t *testing.T) {
+	t.Run("invalid_input", func(t *testing.T) {
+		field := Decimal("foo")
+		assert.NotNil(t, field.Validate("19.99"))
+		assert.NotNil(t, field.Validate(true))
+		assert.NotNil(t, field.Validate(json.Number("1e100000")))
+	})
+	t.Run("nil_value", func(t *testing.T) {
+		assert.Nil(t, Decimal("foo").Validate(nil))
+		assert.NotNil(t, Decimal("foo").Required().Validate(nil))
+	})
+	t.Run("valid_value", func(t *testing.T) {
+		field := Decimal("foo")
+		assert.Nil(t, field.Validate(json.Number("19.99")))
+		assert.Nil(t, field.Validate(19.99))
+		assert.Nil(t, field.Validate(5))
+		assert.Nil(t, field.Validate(uint64(5)))
+	})
+	t.Run("precision_and_scale", func(t *testing.T) {
+		field := Decimal("foo").Precision(5).Scale(2)
+		assert.Nil(t, field.Validate(json.Number("123.45")))
+		assert.Nil(t, field.Validate(json.Number("123.450")))
+		assert.Nil(t, field.Validate(json.Number("0.05")))
+		assert.Nil(t, field.Validate(json.Number("1.2345e2")))
+		assert.Nil(t, field.Validate(json.Number("0.00000")))
+		assert.Nil(t, Decimal("foo").Precision(3).Validate(json.Number("0e5")))
+		assert.Nil(t, Decimal("foo").Scale(0).Validate(json.Number("0.000")))
+
+		errs := toValidationErrors(field.Validate(json.Number("123.456")))
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "precision", errs[0].Rule)
+		assert.Equal(t, 5, errs[0].Expected)
+		assert.Equal(t, "scale", errs[1].Rule)
+		assert.Equal(t, "Value for foo should have at most 2 decimal places", errs[1].Message)
+
+		errs = toValidationErrors(field.Validate(json.Number("1234.5")))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "Value for foo should have at most 5 digits", errs[0].Message)
+	})
+	t.Run("min_and_max", func(t *testing.T) {
+		field := Decimal("foo").Min("0.01").Max("999.99")
+		assert.Nil(t, field.Validate(json.Number("0.01")))
+		assert.Nil(t, field.Validate(json.Number("999.99")))
+
+		errs := toValidationErrors(field.Validate(json.Number("0.009")))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "min", errs[0].Rule)
+		assert.Equal(t, "0.01", errs[0].Expected)
+
+		errs = toValidationErrors(field.Validate(json.Number("999.990000000000000001")))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "Value for foo should be at most 999.99", errs[0].Message)
+	})
+	t.Run("ranges", func(t *testing.T) {
+		field := Decimal("foo").Range("0.1", "0.2").Range("1", "2")
+		assert.Nil(t, field.Validate(json.Number("0.15")))
+		assert.Nil(t, field.Validate(json.Number("2")))
+
+		errs := toValidationErrors(field.Validate(json.Number("0.3")))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "ranges", errs[0].Rule)
+		assert.Equal(t, []DecimalRangeSpec{{Start: "0.1", End: "0.2"}, {Start: "1", End: "2"}}, errs[0].Expected)
+	})
+	t.Run("allow_strings", func(t *testing.T) {
+		field := Decimal("foo").AllowStrings().Scale(2)
+		assert.Nil(t, field.Validate("19.99"))
+		assert.NotNil(t, field.Validate("19.999"))
+
+		errs := toValidationErrors(field.Validate("abc"))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "Value for foo should be a decimal number or a numeric string", errs[0].Message)
+		assert.NotNil(t, field.Validate(" 1"))
+		assert.NotNil(t, field.Validate("01"))
+	})
+}
+
+func TestDecimalField_JSON(t *testing.T) {
+	schema := NewSchema(Decimal("price").Required().Scale(2).Min("0.1").Max("0.3"))
+
+	t.Run("exact", func(t *testing.T) {
+		assert.Nil(t, schema.ValidateString(`{"price": 0.3}`))
+		assert.Nil(t, schema.ValidateString(`{"price": 0.10}`))
+		// 0.1 + 0.2 is not 0.3 in float64, but decimals are compared exactly
+		errs := schema.ValidateDetailed([]byte(`{"price": 0.30000000000000004}`))
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "scale", errs[0].Rule)
+		assert.Equal(t, "max", errs[1].Rule)
+		assert.Equal(t, json.Number("0.30000000000000004"), errs[1].Actual)
+	})
+	t.Run("type", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"price": "0.2"}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "type", errs[0].Rule)
+		assert.Equal(t, decimalType, errs[0].Expected)
+		assert.NotNil(t, schema.ValidateString(`{}`))
+		assert.NotNil(t, schema.ValidateString(`{"price": null}`))
+		nullable := NewSchema(Decimal("price").Nullable())
+		assert.Nil(t, nullable.ValidateString(`{"price": null}`))
+	})
+	t.Run("custom", func(t *testing.T) {
+		var received interface{}
+		schema := NewSchema(Decimal("price").Custom("record", func(value interface{}) error {
+			received = value
+			return nil
+		}))
+		assert.Nil(t, schema.ValidateString(`{"price": 12345678901234567890.12}`))
+		assert.Equal(t, json.Number("12345678901234567890.12"), received)
+	})
+	t.Run("values", func(t *testing.T) {
+		assert.Nil(t, schema.ValidateValue(map[string]interface{}{"price": json.Number("0.25")}))
+		assert.NotNil(t, schema.ValidateValue(map[string]interface{}{"price": "0.25"}))
+
+		values, err := schema.ValidateValues(url.Values{"price": {"0.25"}})
+		assert.Nil(t, err)
+		assert.Equal(t, json.Number("0.25"), values["price"])
+		_, err = schema.ValidateValues(url.Values{"price": {"0.255"}})
+		assert.NotNil(t, err)
+	})
+	t.Run("default", func(t *testing.T) {
+		schema := NewSchema(Decimal("discount").Scale(2).Default("0.00"))
+		output, err := schema.Apply([]byte(`{}`))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"discount":0.00}`, string(output))
+
+		_, err = NewSchema(Decimal("discount").Scale(2).Default("0.001")).Compile()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestDecimalField_Compile(t *testing.T) {
+	_, err := NewSchema(Decimal("foo").Precision(10).Scale(2).Min("0").Range("1", "2")).Compile()
+	assert.Nil(t, err)
+
+	_, err = NewSchema(Decimal("foo").Precision(0)).Compile()
+	assert.NotNil(t, err)
+	_, err = NewSchema(Decimal("foo").Scale(-1)).Compile()
+	assert.NotNil(t, err)
+	_, err = NewSchema(Decimal("foo").Precision(2).Scale(3)).Compile()
+	assert.NotNil(t, err)
+	_, err = NewSchema(Decimal("foo").Min("abc")).Compile()
+	assert.NotNil(t, err)
+	_, err = NewSchema(Decimal("foo").Range("1", "x")).Compile()
+	assert.NotNil(t, err)
+}
+
+func TestDecimalField_MarshalJSON(t *testing.T) {
+	field := Decimal("price").Required().Precision(10).Scale(2).Min("0.01").Range("1", "2").AllowStrings().Default("1.00")
+	content, err := json.Marshal(field)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"name": "price",
+		"type": "decimal",
+		"required": true,
+		"precision": 10,
+		"scale": 2,
+		"min": "0.01",
+		"ranges": [{"start": "1", "end": "2"}],
+		"allow_strings": true,
+		"default": 1.00
+	}`, string(content))
+
+	schema, err := ReadFromBytes(append([]byte(`{"fields": [`), append(content, []byte(`]}`)...)...))
+	assert.Nil(t, err)
+	assert.Nil(t, schema.ValidateString(`{"price": "1.50"}`))
+	assert.NotNil(t, schema.ValidateString(`{"price": 1.505}`))
+}
+
+func TestNewDecimal(t *testing.T) {
+	schema, err := ReadFromString(`{"fields": [
+		{"name": "price", "type": "decimal", "scale": 2, "min": 0.1, "max": "100", "ranges": [{"start": 0, "end": 50.5}], "default": 0.5}
+	]}`)
+	assert.Nil(t, err)
+	field := schema.Fields[0].(*DecimalField)
+	assert.Equal(t, "0.1", field.min)
+	assert.Equal(t, "100", field.max)
+	assert.Equal(t, []DecimalRangeSpec{{Start: "0", End: "50.5"}}, field.rangeSpecs())
+	assert.Equal(t, json.Number("0.5"), field.defaultValue)
+	assert.True(t, field.scaleValidation)
+	assert.False(t, field.precisionValidation)
+
+	assert.Nil(t, schema.ValidateString(`{"price": 50.5}`))
+	assert.NotNil(t, schema.ValidateString(`{"price": 50.51}`))
+
+	_, err = ReadFromString(`{"fields": [{"type": "decimal"}]}`)
+	assert.NotNil(t, err)
+
+	// numeric bounds keep their exact text, they are not rounded by float64
+	schema, err = ReadFromString(`{"fields": [
+		{"name": "total", "type": "decimal", "max": 99999999999999999.99, "ranges": [{"start": 0.1, "end": 12345678901234567890.12}], "default": 99999999999999999.99},
+		{"name": "prices", "type": "array", "items": {"name": "price", "type": "decimal", "max": 99999999999999999.99}}
+	]}`)
+	assert.Nil(t, err)
+	field = schema.Fields[0].(*DecimalField)
+	assert.Equal(t, "99999999999999999.99", field.max)
+	assert.Equal(t, []DecimalRangeSpec{{Start: "0.1", End: "12345678901234567890.12"}}, field.rangeSpecs())
+	assert.Equal(t, json.Number("99999999999999999.99"), field.defaultValue)
+	assert.Nil(t, schema.ValidateString(`{"total": 99999999999999999.99, "prices": [99999999999999999.99]}`))
+	assert.NotNil(t, schema.ValidateString(`{"total": 100000000000000000}`))
+	assert.NotNil(t, schema.ValidateString(`{"prices": [100000000000000000]}`))
+
+	content, err := json.Marshal(schema)
+	assert.Nil(t, err)
+	parsed, err := ReadFromBytes(content)
+	assert.Nil(t, err)
+	assert.NotNil(t, parsed.ValidateString(`{"prices": [100000000000000000]}`))
+	assert.Equal(t, json.Number("99999999999999999.99"), parsed.Fields[0].(*DecimalField).defaultValue)
+}
+
+func TestDecimalField_JSONSchema(t *testing.T) {
+	content, err := NewSchema(Decimal("price").Scale(2).Min("0.01").Max("99.99")).ToJSONSchema()
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `"minimum":0.01`)
+	assert.Contains(t, string(content), `"maximum":99.99`)
+	assert.Contains(t, string(content), `"multipleOf":0.01`)
+
+	_, err = NewSchema(Decimal("price").Precision(10)).ToJSONSchema()
+	assert.NotNil(t, err)
+	_, err = NewSchema(Decimal("price").AllowStrings()).ToJSONSchema()
+	assert.NotNil(t, err)
+}
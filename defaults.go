@@ -0,0 +1,236 @@
+package vjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// defaultedField is implemented by fields which can have a default value.
+type defaultedField interface {
+	getDefault() (interface{}, bool)
+}
+
+// defaultJSON returns the json of the default value of a field, or nil if the field has no default value.
+func defaultJSON(field Field) ([]byte, error) {
+	defaulted, ok := field.(defaultedField)
+	if !ok {
+		return nil, nil
+	}
+	value, found := defaulted.getDefault()
+	if !found {
+		return nil, nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not marshal default value of field %s", field.GetName())
+	}
+	return content, nil
+}
+
+// checkDefault returns an error if the default value of the field is invalid according to the field.
+func checkDefault(field Field) error {
+	content, err := defaultJSON(field)
+	if err != nil || content == nil {
+		return err
+	}
+	err = validateResult(field, gjson.ParseBytes(content))
+	if err != nil {
+		return errors.Wrapf(err, "default value of field %s is invalid", field.GetName())
+	}
+	return nil
+}
+
+// setDefault sets the default value of a field of a built-in type.
+func setDefault(field Field, value interface{}) {
+	switch f := field.(type) {
+	case *IntegerField:
+		f.defaultValue = value
+	case *FloatField:
+		f.defaultValue = value
+	case *StringField:
+		f.defaultValue = value
+	case *BooleanField:
+		f.defaultValue = value
+	case *ArrayField:
+		f.defaultValue = value
+	case *ObjectField:
+		f.defaultValue = value
+	case *CombinatorField:
+		f.defaultValue = value
+	case *RefField:
+		f.defaultValue = value
+	case *DecimalField:
+		f.defaultValue = value
+	}
+}
+
+// Apply fills the missing fields of a json object with their default values, validates the result according to the
+// Schema and returns it. missing fields of nested objects, including objects in arrays, are filled too.
+// present values are kept as they are, and a JSON null is not replaced with a default value.
+// the normalised json is not returned if it is invalid, and the validation error is returned.
+func (s *Schema) Apply(input []byte) ([]byte, error) {
+	if !gjson.ValidBytes(input) {
+		return nil, invalidJSONError()
+	}
+	value := gjson.ParseBytes(input)
+	output, err := s.applyDefaults(value, make(map[Field]bool))
+	if err != nil {
+		return nil, err
+	}
+	// the input is validated as it is parsed if no default is inserted. the normalised json is built from valid
+	// values, so its syntax is not checked again.
+	if string(output) != value.Raw {
+		value = gjson.ParseBytes(output)
+	}
+	err = s.validateJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// applyDefaults returns a json value with the default values of the schema fields. a value which has no missing
+// fields is returned unchanged. inserting holds the fields whose default values are being normalised.
+func (s *Schema) applyDefaults(value gjson.Result, inserting map[Field]bool) ([]byte, error) {
+	if s.root != nil {
+		return applyFieldDefaults(s.root, value, inserting)
+	}
+	if !value.IsObject() {
+		return []byte(value.Raw), nil
+	}
+
+	changed := false
+	present := make(map[string]bool)
+	var members [][]byte
+	var result error
+	value.ForEach(func(key, item gjson.Result) bool {
+		name := key.String()
+		present[name] = true
+		field := s.propertyField(name)
+		content := []byte(item.Raw)
+		if field != nil {
+			applied, err := applyFieldDefaults(field, item, inserting)
+			if err != nil {
+				result = err
+				return false
+			}
+			if !bytes.Equal(applied, content) {
+				changed = true
+				content = applied
+			}
+		}
+		members = append(members, member([]byte(key.Raw), content))
+		return true
+	})
+	if result != nil {
+		return nil, result
+	}
+
+	for _, field := range s.Fields {
+		name := field.GetName()
+		if present[name] {
+			continue
+		}
+		content, err := defaultJSON(field)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			continue
+		}
+		// the default value is normalised too, e.g. an object default gets the defaults of its fields. a default which
+		// is inserted while the default of the same field is normalised is kept as it is, since a recursive definition
+		// would insert it forever.
+		if !inserting[field] {
+			inserting[field] = true
+			content, err = applyFieldDefaults(field, gjson.ParseBytes(content), inserting)
+			delete(inserting, field)
+			if err != nil {
+				return nil, err
+			}
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not marshal name of field %s", name)
+		}
+		present[name] = true
+		changed = true
+		members = append(members, member(key, content))
+	}
+
+	if !changed {
+		return []byte(value.Raw), nil
+	}
+	return append(append([]byte{'{'}, bytes.Join(members, []byte{','})...), '}'), nil
+}
+
+// propertyField returns the field which validates a property of a json object, or nil if the property is not
+// validated by a field.
+func (s *Schema) propertyField(name string) Field {
+	for _, field := range s.Fields {
+		if field.GetName() == name {
+			return field
+		}
+	}
+	if s.additionalProperties == additionalPropertiesValidate {
+		return s.additionalField
+	}
+	return nil
+}
+
+// member returns a member of a json object with the given key and value.
+func member(key, value []byte) []byte {
+	return append(append(append([]byte{}, key...), ':'), value...)
+}
+
+// applyFieldDefaults returns a json value with the default values of the fields which are nested in a field.
+func applyFieldDefaults(field Field, value gjson.Result, inserting map[Field]bool) ([]byte, error) {
+	switch f := field.(type) {
+	case *ObjectField:
+		if value.IsObject() {
+			return f.schema.applyDefaults(value, inserting)
+		}
+	case *ArrayField:
+		if value.IsArray() {
+			return applyItemsDefaults(f.items, value, inserting)
+		}
+	case *RefField:
+		// an unresolved ref is reported by the validation
+		definition, err := f.definition()
+		if err == nil {
+			return applyFieldDefaults(definition, value, inserting)
+		}
+	}
+	return []byte(value.Raw), nil
+}
+
+// applyItemsDefaults returns a json array with the default values of the fields which are nested in its items.
+func applyItemsDefaults(items Field, value gjson.Result, inserting map[Field]bool) ([]byte, error) {
+	if items == nil {
+		return []byte(value.Raw), nil
+	}
+	changed := false
+	var elements [][]byte
+	var result error
+	value.ForEach(func(_, item gjson.Result) bool {
+		applied, err := applyFieldDefaults(items, item, inserting)
+		if err != nil {
+			result = err
+			return false
+		}
+		if !bytes.Equal(applied, []byte(item.Raw)) {
+			changed = true
+		}
+		elements = append(elements, applied)
+		return true
+	})
+	if result != nil {
+		return nil, result
+	}
+	if !changed {
+		return []byte(value.Raw), nil
+	}
+	return append(append([]byte{'['}, bytes.Join(elements, []byte{','})...), ']'), nil
+}
@@ -0,0 +1,133 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchema_Apply(t *testing.T) {
+	schema := NewSchema(
+		String("name").Required(),
+		Integer("page_size").Min(1).Default(20),
+		Boolean("active").Default(false),
+		Array("tags", String("tag")).Default([]interface{}{}),
+		Object("settings", NewSchema(
+			String("theme").Choices("light", "dark").Default("light"),
+			Float("ratio").Default(0.5),
+		)).Default(map[string]interface{}{}),
+		Array("items", Object("item", NewSchema(
+			String("sku").Required(),
+			Integer("quantity").Default(1),
+		))),
+		String("comment"),
+	)
+
+	t.Run("missing_fields", func(t *testing.T) {
+		output, err := schema.Apply([]byte(`{"name": "foo", "items": [{"sku": "a"}, {"sku": "b", "quantity": 3}]}`))
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{
+			"name": "foo",
+			"page_size": 20,
+			"active": false,
+			"tags": [],
+			"settings": {"theme": "light", "ratio": 0.5},
+			"items": [{"sku": "a", "quantity": 1}, {"sku": "b", "quantity": 3}]
+		}`, string(output))
+	})
+	t.Run("present_fields", func(t *testing.T) {
+		input := `{"name": "foo", "page_size": 5, "active": true, "tags": ["a"], "settings": {"theme": "dark", "ratio": 1}}`
+		output, err := schema.Apply([]byte(input))
+		assert.Nil(t, err)
+		assert.Equal(t, input, string(output))
+	})
+	t.Run("nested_object", func(t *testing.T) {
+		output, err := schema.Apply([]byte(`{"name": "foo", "settings": {"theme": "dark"}, "page_size": 5, "active": true, "tags": []}`))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"name":"foo","settings":{"theme":"dark","ratio":0.5},"page_size":5,"active":true,"tags":[]}`, string(output))
+	})
+	t.Run("null", func(t *testing.T) {
+		_, err := schema.Apply([]byte(`{"name": "foo", "page_size": null}`))
+		assert.NotNil(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		output, err := schema.Apply([]byte(`{"page_size": 0}`))
+		assert.Nil(t, output)
+		errs := toValidationErrors(err)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "/name", errs[0].Path)
+		assert.Equal(t, "/page_size", errs[1].Path)
+
+		_, err = schema.Apply([]byte(`{"name": `))
+		assert.NotNil(t, err)
+	})
+	t.Run("root", func(t *testing.T) {
+		schema := NewRootSchema(Array("", Object("user", NewSchema(String("role").Default("member")))))
+		output, err := schema.Apply([]byte(`[{}, {"role": "admin"}]`))
+		assert.Nil(t, err)
+		assert.Equal(t, `[{"role":"member"},{"role": "admin"}]`, string(output))
+	})
+	t.Run("ref", func(t *testing.T) {
+		schema := NewSchema(Ref("address", "address").Default(map[string]interface{}{"city": "Paris"})).
+			Define("address", Object("address", NewSchema(String("city"), String("country").Default("FR"))))
+		output, err := schema.Apply([]byte(`{}`))
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"address": {"city": "Paris", "country": "FR"}}`, string(output))
+	})
+	t.Run("recursive_ref", func(t *testing.T) {
+		schema := NewSchema(Ref("root", "node")).
+			Define("node", Object("node", NewSchema(String("v"), Ref("child", "node").Default(map[string]interface{}{}))))
+		output, err := schema.Apply([]byte(`{"root": {}}`))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"root":{"child":{"child":{}}}}`, string(output))
+
+		output, err = schema.Apply([]byte(`{"root": {"child": {"child": {"v": "a"}}}}`))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"root":{"child":{"child":{"v":"a","child":{"child":{}}}}}}`, string(output))
+	})
+	t.Run("validator", func(t *testing.T) {
+		validator, err := schema.Compile()
+		assert.Nil(t, err)
+		output, err := validator.Apply([]byte(`{"name": "foo"}`))
+		assert.Nil(t, err)
+		assert.Contains(t, string(output), `"page_size":20`)
+	})
+}
+
+func TestSchema_Default(t *testing.T) {
+	t.Run("spec", func(t *testing.T) {
+		schema, err := ReadFromString(`{"fields": [
+			{"name": "page_size", "type": "integer", "default": 20},
+			{"name": "sort", "type": "string", "choices": ["asc", "desc"], "default": "asc"},
+			{"name": "active", "type": "boolean", "default": false}
+		]}`)
+		assert.Nil(t, err)
+		output, err := schema.Apply([]byte(`{}`))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"page_size":20,"sort":"asc","active":false}`, string(output))
+
+		content, err := json.Marshal(schema)
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `"default":20`)
+		assert.Contains(t, string(content), `"default":false`)
+	})
+	t.Run("compile", func(t *testing.T) {
+		_, err := NewSchema(String("sort").Choices("asc", "desc").Default("random")).Compile()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "default value of field sort is invalid")
+
+		_, err = NewSchema(Integer("page_size").Min(1).Default(20)).Compile()
+		assert.Nil(t, err)
+	})
+	t.Run("json_schema", func(t *testing.T) {
+		content, err := NewSchema(Integer("page_size").Default(20)).ToJSONSchema()
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `"default":20`)
+
+		schema, err := FromJSONSchema(content)
+		assert.Nil(t, err)
+		output, err := schema.Apply([]byte(`{}`))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"page_size":20}`, string(output))
+	})
+}
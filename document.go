@@ -0,0 +1,84 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// documentPosition is the position of a value in a YAML or TOML document.
+// file is the name of the included file which contains the value, and it is empty for the file which is read.
+// line and column are zero in json files, which have no positions.
+type documentPosition struct {
+	file   string
+	line   int
+	column int
+}
+
+// documentPositions maps JSON Pointers of the values of a document to their positions in the source document.
+type documentPositions map[string]documentPosition
+
+// find returns the position of a pointer, or the position of its nearest parent if the pointer has no position.
+func (p documentPositions) find(pointer string) documentPosition {
+	for {
+		if position, found := p[pointer]; found {
+			return position
+		}
+		if pointer == "" {
+			return documentPosition{line: 1, column: 1}
+		}
+		pointer = pointer[:strings.LastIndex(pointer, "/")]
+	}
+}
+
+// readFromDocument parses a Schema from a decoded YAML or TOML document.
+// the document is converted to json, so the Schema is the same as parsing the equivalent json spec.
+func readFromDocument(document interface{}, positions documentPositions) (*Schema, error) {
+	input, err := json.Marshal(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert schema spec to json")
+	}
+	schema, err := ReadFromBytes(input)
+	if err != nil {
+		return nil, locateSpecErrors(input, positions, err)
+	}
+	return schema, nil
+}
+
+// validateDocument validates a decoded YAML or TOML document. the document is converted to json, so it is validated
+// like the equivalent json object, and positions of invalid values in the source document are added to the errors.
+func (s *Schema) validateDocument(document interface{}, positions documentPositions) error {
+	input, err := json.Marshal(document)
+	if err != nil {
+		return newValidationError("", invalidRule, nil, nil, "could not convert document to json: %v", err)
+	}
+	return addPositions(s.ValidateBytes(input), positions)
+}
+
+// addPositions sets Line and Column of every validation error in err by its path.
+func addPositions(err error, positions documentPositions) error {
+	if err == nil {
+		return nil
+	}
+	var result error
+	for _, validationError := range toValidationErrors(err) {
+		validationError := positionValidationError(validationError, positions)
+		result = multierror.Append(result, &validationError)
+	}
+	return result
+}
+
+func positionValidationError(validationError ValidationError, positions documentPositions) ValidationError {
+	position := positions.find(validationError.Path)
+	validationError.Line = position.line
+	validationError.Column = position.column
+	if len(validationError.Causes) > 0 {
+		causes := make([]ValidationError, 0, len(validationError.Causes))
+		for _, cause := range validationError.Causes {
+			causes = append(causes, positionValidationError(cause, positions))
+		}
+		validationError.Causes = causes
+	}
+	return validationError
+}
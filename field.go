@@ -1,6 +1,10 @@
 package vjson
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
 
 // Field is the abstraction on a field in a json.
 // different field types can be implemented with implementing this interface.
@@ -9,3 +13,80 @@ type Field interface {
 	GetName() string
 	Validate(interface{}) error
 }
+
+// resultValidator is implemented by fields which validate a parsed json value directly,
+// without converting it to Go values. it is used for walking nested objects and arrays.
+type resultValidator interface {
+	validateResult(value gjson.Result) error
+}
+
+// nullableField is implemented by fields which distinguish a JSON null from a missing value.
+// a null is accepted only if the field is nullable.
+type nullableField interface {
+	isNullable() bool
+}
+
+// compilable is implemented by fields which have to be checked before validation.
+type compilable interface {
+	compile() error
+}
+
+// validateResult validates a parsed json value with the given field.
+func validateResult(field Field, value gjson.Result) error {
+	if value.Exists() && value.Type == gjson.Null {
+		if _, ok := field.(nullableField); ok {
+			return validateNull(field)
+		}
+	}
+	if validator, ok := field.(resultValidator); ok {
+		err := validator.validateResult(value)
+		if err != nil || !value.Exists() {
+			return err
+		}
+		// custom validators of fields which walk the value are not called by validateResult
+		if custom, ok := field.(customValidated); ok && len(custom.getValidators()) > 0 {
+			return custom.getValidators().validate(field.GetName(), resultValue(field, value))
+		}
+		return nil
+	}
+	return field.Validate(value.Value())
+}
+
+// resultValue returns the value of a parsed json value which is passed to custom validators of a field.
+// decimal fields receive numbers as json.Number, so they do not lose precision.
+func resultValue(field Field, value gjson.Result) interface{} {
+	if decimalField, ok := field.(*DecimalField); ok {
+		return decimalField.resultValue(value)
+	}
+	return value.Value()
+}
+
+// validateNull validates a JSON null which is present in a json object or array with the given field.
+// fields which do not implement nullableField receive null as a missing value.
+func validateNull(field Field) error {
+	if nullable, ok := field.(nullableField); ok {
+		if nullable.isNullable() {
+			return nil
+		}
+		return newValidationError(field.GetName(), nullableRule, false, nil, "Value for %s should not be null", field.GetName())
+	}
+	return field.Validate(nil)
+}
+
+// compileField checks a field and the fields nested in it.
+func compileField(field Field) error {
+	if field == nil {
+		return errMissingField
+	}
+	err := checkValidators(field)
+	if err != nil {
+		return errors.Wrapf(err, "custom validators of field %s are invalid", field.GetName())
+	}
+	if c, ok := field.(compilable); ok {
+		err = c.compile()
+		if err != nil {
+			return err
+		}
+	}
+	return checkDefault(field)
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/go-multierror"
-	"github.com/pkg/errors"
 	"strings"
 )
 
@@ -17,6 +16,7 @@ type floatRange struct {
 type FloatField struct {
 	name     string
 	required bool
+	nullable bool
 
 	min           float64
 	minValidation bool
@@ -29,6 +29,9 @@ type FloatField struct {
 
 	rangeValidation bool
 	ranges          []floatRange
+
+	validators   customValidators
+	defaultValue interface{}
 }
 
 // To Force Implementing Field interface by IntegerField
@@ -39,41 +42,71 @@ func (f *FloatField) GetName() string {
 	return f.name
 }
 
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (f *FloatField) Custom(name string, fn ValidatorFunc) *FloatField {
+	f.validators = f.validators.add(name, fn)
+	return f
+}
+
+func (f *FloatField) getValidators() customValidators {
+	return f.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (f *FloatField) Default(value float64) *FloatField {
+	f.defaultValue = value
+	return f
+}
+
+func (f *FloatField) getDefault() (interface{}, bool) {
+	return f.defaultValue, f.defaultValue != nil
+}
+
 // Validate is used for validating a value. it returns an error if the value is invalid.
 func (f *FloatField) Validate(v interface{}) error {
+	err := f.validateValue(v)
+	if err != nil || v == nil {
+		return err
+	}
+	return f.validators.validate(f.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (f *FloatField) validateValue(v interface{}) error {
 	if v == nil {
 		if !f.required {
 			return nil
 		}
-		return errors.Errorf("Value for %s field is required", f.name)
+		return requiredError(f.name)
 	}
 
-	value, ok := v.(float64)
-
+	value, ok := toFloat(v)
 	if !ok {
-		return errors.Errorf("Value for %s should be a float number", f.name)
+		return newValidationError(f.name, typeRule, floatType, v, "Value for %s should be a float number", f.name)
 	}
 
 	var result error
 	if f.signValidation && f.positive {
 		if value < 0 {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be a positive float", f.name))
+			result = multierror.Append(result, newValidationError(f.name, "positive", true, value, "Value for %s should be a positive float", f.name))
 		}
 	} else if f.signValidation && !f.positive {
 		if value > 0 {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be a negative float", f.name))
+			result = multierror.Append(result, newValidationError(f.name, "positive", false, value, "Value for %s should be a negative float", f.name))
 		}
 	}
 
 	if f.minValidation {
 		if value < f.min {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be at least %f", f.name, f.min))
+			result = multierror.Append(result, newValidationError(f.name, "min", f.min, value, "Value for %s should be at least %f", f.name, f.min))
 		}
 	}
 
 	if f.maxValidation {
 		if value > f.max {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be at most %f", f.name, f.max))
+			result = multierror.Append(result, newValidationError(f.name, "max", f.max, value, "Value for %s should be at most %f", f.name, f.max))
 		}
 	}
 
@@ -91,7 +124,7 @@ func (f *FloatField) Validate(v interface{}) error {
 			for _, r := range f.ranges {
 				ranges.WriteString(fmt.Sprintf("[%f,%f] ", r.start, r.end))
 			}
-			result = multierror.Append(result, errors.Errorf("Value for %s should be in one of these ranges: %s", f.name, ranges.String()))
+			result = multierror.Append(result, newValidationError(f.name, "ranges", f.rangeSpecs(), value, "Value for %s should be in one of these ranges: %s", f.name, ranges.String()))
 		}
 	}
 
@@ -104,6 +137,16 @@ func (f *FloatField) Required() *FloatField {
 	return f
 }
 
+// Nullable is called to accept null as a valid value of the field in a JSON
+func (f *FloatField) Nullable() *FloatField {
+	f.nullable = true
+	return f
+}
+
+func (f *FloatField) isNullable() bool {
+	return f.nullable
+}
+
 // Positive is called when we want to force the value to be positive in validation.
 func (f *FloatField) Positive() *FloatField {
 	f.signValidation = true
@@ -139,7 +182,7 @@ func (f *FloatField) Range(start, end float64) *FloatField {
 	return f
 }
 
-func (f *FloatField) MarshalJSON() ([]byte, error) {
+func (f *FloatField) rangeSpecs() []FloatRangeSpec {
 	ranges := make([]FloatRangeSpec, 0, len(f.ranges))
 	for _, r := range f.ranges {
 		ranges = append(ranges, FloatRangeSpec{
@@ -147,14 +190,21 @@ func (f *FloatField) MarshalJSON() ([]byte, error) {
 			End:   r.end,
 		})
 	}
+	return ranges
+}
+
+func (f *FloatField) MarshalJSON() ([]byte, error) {
 	return json.Marshal(FloatFieldSpec{
-		Name:     f.name,
-		Type:     floatType,
-		Required: f.required,
-		Min:      f.min,
-		Max:      f.max,
-		Positive: f.positive,
-		Ranges:   ranges,
+		Name:       f.name,
+		Type:       floatType,
+		Required:   f.required,
+		Nullable:   f.nullable,
+		Min:        f.min,
+		Max:        f.max,
+		Positive:   f.positive,
+		Ranges:     f.rangeSpecs(),
+		Validators: f.validators.names(),
+		Default:    f.defaultValue,
 	})
 }
 
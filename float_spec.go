@@ -8,13 +8,16 @@ type FloatRangeSpec struct {
 
 // FloatFieldSpec is a type used for parsing an FloatField
 type FloatFieldSpec struct {
-	Name     string           `mapstructure:"name" json:"name"`
-	Type     fieldType        `json:"type"`
-	Required bool             `mapstructure:"required" json:"required,omitempty"`
-	Min      float64          `mapstructure:"min" json:"min,omitempty"`
-	Max      float64          `mapstructure:"max" json:"max,omitempty"`
-	Positive bool             `mapstructure:"positive" json:"positive,omitempty"`
-	Ranges   []FloatRangeSpec `mapstructure:"ranges" json:"ranges,omitempty"`
+	Name       string           `mapstructure:"name" json:"name"`
+	Type       fieldType        `json:"type"`
+	Required   bool             `mapstructure:"required" json:"required,omitempty"`
+	Nullable   bool             `mapstructure:"nullable" json:"nullable,omitempty"`
+	Min        float64          `mapstructure:"min" json:"min,omitempty"`
+	Max        float64          `mapstructure:"max" json:"max,omitempty"`
+	Positive   bool             `mapstructure:"positive" json:"positive,omitempty"`
+	Ranges     []FloatRangeSpec `mapstructure:"ranges" json:"ranges,omitempty"`
+	Validators []string         `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{}      `mapstructure:"default" json:"default,omitempty"`
 }
 
 // NewFloat receives an FloatFieldSpec and returns and FloatField
@@ -29,6 +32,7 @@ func NewFloat(spec FloatFieldSpec, minValidation, maxValidation, signValidation,
 	return &FloatField{
 		name:            spec.Name,
 		required:        spec.Required,
+		nullable:        spec.Nullable,
 		min:             spec.Min,
 		minValidation:   minValidation,
 		max:             spec.Max,
@@ -37,5 +41,7 @@ func NewFloat(spec FloatFieldSpec, minValidation, maxValidation, signValidation,
 		positive:        spec.Positive,
 		rangeValidation: rangeValidation,
 		ranges:          ranges,
+		validators:      namedValidators(spec.Validators),
+		defaultValue:    spec.Default,
 	}
 }
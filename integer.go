@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"math/big"
 	"strings"
 )
 
@@ -17,6 +19,7 @@ type intRange struct {
 type IntegerField struct {
 	name     string
 	required bool
+	nullable bool
 
 	min           int
 	minValidation bool
@@ -29,6 +32,14 @@ type IntegerField struct {
 
 	rangeValidation bool
 	ranges          []intRange
+
+	multipleOf           int
+	multipleOfValidation bool
+
+	big bool
+
+	validators   customValidators
+	defaultValue interface{}
 }
 
 // To Force Implementing Field interface by IntegerField
@@ -39,57 +50,88 @@ func (i *IntegerField) GetName() string {
 	return i.name
 }
 
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (i *IntegerField) Custom(name string, fn ValidatorFunc) *IntegerField {
+	i.validators = i.validators.add(name, fn)
+	return i
+}
+
+func (i *IntegerField) getValidators() customValidators {
+	return i.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (i *IntegerField) Default(value int) *IntegerField {
+	i.defaultValue = value
+	return i
+}
+
+func (i *IntegerField) getDefault() (interface{}, bool) {
+	return i.defaultValue, i.defaultValue != nil
+}
+
 // Validate is used for validating a value. it returns an error if the value is invalid.
 func (i *IntegerField) Validate(v interface{}) error {
+	err := i.validateValue(v)
+	if err != nil || v == nil {
+		return err
+	}
+	return i.validators.validate(i.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (i *IntegerField) validateValue(v interface{}) error {
 	if v == nil {
 		if !i.required {
 			return nil
 		}
-		return errors.Errorf("Value for %s field is required", i.name)
+		return requiredError(i.name)
 	}
-	var value int
-	var intOK bool
-
-	//gjson library returns float64 when field value is number
-	floatValue, floatOK := v.(float64)
-
-	value, intOK = v.(int)
 
-	if !floatOK && !intOK {
-		return errors.Errorf("Value for %s should be a number", i.name)
+	number, ok := bigInteger(v)
+	if !ok {
+		return newValidationError(i.name, typeRule, integerType, v, "Value for %s should be a number", i.name)
 	}
-
-	if floatOK {
-		value = int(floatValue)
+	if number == nil {
+		if text, ok := v.(json.Number); ok && exponentOutOfRange(string(text)) {
+			return newValidationError(i.name, "exponent", maxExponent, v, "Value for %s is out of range, its exponent should be at most %d", i.name, maxExponent)
+		}
+		return newValidationError(i.name, typeRule, integerType, v, "Value for %s should be an integer", i.name)
+	}
+	if !i.big && !number.IsInt64() {
+		return newValidationError(i.name, typeRule, integerType, v, "Value for %s should be a 64-bit integer", i.name)
 	}
+	value := integerActual(number)
 
 	var result error
 	if i.signValidation && i.positive {
-		if value < 0 {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be a positive integer", i.name))
+		if number.Sign() < 0 {
+			result = multierror.Append(result, newValidationError(i.name, "positive", true, value, "Value for %s should be a positive integer", i.name))
 		}
 	} else if i.signValidation && !i.positive {
-		if value > 0 {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be a negative integer", i.name))
+		if number.Sign() > 0 {
+			result = multierror.Append(result, newValidationError(i.name, "positive", false, value, "Value for %s should be a negative integer", i.name))
 		}
 	}
 
 	if i.minValidation {
-		if value < i.min {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be at least %d", i.name, i.min))
+		if number.Cmp(big.NewInt(int64(i.min))) < 0 {
+			result = multierror.Append(result, newValidationError(i.name, "min", i.min, value, "Value for %s should be at least %d", i.name, i.min))
 		}
 	}
 
 	if i.maxValidation {
-		if value > i.max {
-			result = multierror.Append(result, errors.Errorf("Value for %s should be at most %d", i.name, i.max))
+		if number.Cmp(big.NewInt(int64(i.max))) > 0 {
+			result = multierror.Append(result, newValidationError(i.name, "max", i.max, value, "Value for %s should be at most %d", i.name, i.max))
 		}
 	}
 
 	if i.rangeValidation {
 		inRange := false
 		for _, r := range i.ranges {
-			if value >= r.start && value <= r.end {
+			if number.Cmp(big.NewInt(int64(r.start))) >= 0 && number.Cmp(big.NewInt(int64(r.end))) <= 0 {
 				inRange = true
 				break
 			}
@@ -100,19 +142,54 @@ func (i *IntegerField) Validate(v interface{}) error {
 			for _, r := range i.ranges {
 				ranges.WriteString(fmt.Sprintf("[%d,%d] ", r.start, r.end))
 			}
-			result = multierror.Append(result, errors.Errorf("Value for %s should be in one of these ranges: %s", i.name, ranges.String()))
+			result = multierror.Append(result, newValidationError(i.name, "ranges", i.rangeSpecs(), value, "Value for %s should be in one of these ranges: %s", i.name, ranges.String()))
+		}
+	}
+
+	// a schema with an invalid multiple_of is rejected by Compile, and it rejects every value if it is not compiled
+	if i.multipleOfValidation && i.multipleOf <= 0 {
+		result = multierror.Append(result, newValidationError(i.name, "multiple_of", i.multipleOf, value, "multiple_of of integer field %s should be positive", i.name))
+	} else if i.multipleOfValidation {
+		if new(big.Int).Rem(number, big.NewInt(int64(i.multipleOf))).Sign() != 0 {
+			result = multierror.Append(result, newValidationError(i.name, "multiple_of", i.multipleOf, value, "Value for %s should be a multiple of %d", i.name, i.multipleOf))
 		}
 	}
 
 	return result
 }
 
+// validateResult validates the raw text of a json number, so integers which can not be represented by float64
+// do not lose precision.
+func (i *IntegerField) validateResult(value gjson.Result) error {
+	if value.Type == gjson.Number {
+		return i.validateValue(json.Number(strings.TrimSpace(value.Raw)))
+	}
+	return i.validateValue(value.Value())
+}
+
+func (i *IntegerField) compile() error {
+	if i.multipleOfValidation && i.multipleOf <= 0 {
+		return errors.Errorf("multiple_of of integer field %s should be positive", i.name)
+	}
+	return nil
+}
+
 // Required is called to make a field required in a JSON
 func (i *IntegerField) Required() *IntegerField {
 	i.required = true
 	return i
 }
 
+// Nullable is called to accept null as a valid value of the field in a JSON
+func (i *IntegerField) Nullable() *IntegerField {
+	i.nullable = true
+	return i
+}
+
+func (i *IntegerField) isNullable() bool {
+	return i.nullable
+}
+
 // Positive is called when we want to force the value to be positive in validation.
 func (i *IntegerField) Positive() *IntegerField {
 	i.signValidation = true
@@ -141,6 +218,20 @@ func (i *IntegerField) Max(value int) *IntegerField {
 	return i
 }
 
+// MultipleOf is called when we want to force the value to be a multiple of the given positive number in validation.
+func (i *IntegerField) MultipleOf(value int) *IntegerField {
+	i.multipleOf = value
+	i.multipleOfValidation = true
+	return i
+}
+
+// Big is called when we want to accept integers which do not fit in 64 bits. values are validated exactly in both
+// modes, but integers out of int64 range are rejected if the field is not big.
+func (i *IntegerField) Big() *IntegerField {
+	i.big = true
+	return i
+}
+
 // Range is called when we want to define valid ranges for an integer value in validation.
 func (i *IntegerField) Range(start, end int) *IntegerField {
 	i.ranges = append(i.ranges, intRange{start: start, end: end})
@@ -148,7 +239,7 @@ func (i *IntegerField) Range(start, end int) *IntegerField {
 	return i
 }
 
-func (i *IntegerField) MarshalJSON() ([]byte, error) {
+func (i *IntegerField) rangeSpecs() []IntRangeSpec {
 	ranges := make([]IntRangeSpec, 0, len(i.ranges))
 	for _, r := range i.ranges {
 		ranges = append(ranges, IntRangeSpec{
@@ -156,15 +247,27 @@ func (i *IntegerField) MarshalJSON() ([]byte, error) {
 			End:   r.end,
 		})
 	}
-	return json.Marshal(IntegerFieldSpec{
-		Name:     i.name,
-		Required: i.required,
-		Min:      i.min,
-		Max:      i.max,
-		Positive: i.positive,
-		Ranges:   ranges,
-		Type:     integerType,
-	})
+	return ranges
+}
+
+func (i *IntegerField) MarshalJSON() ([]byte, error) {
+	spec := IntegerFieldSpec{
+		Name:       i.name,
+		Required:   i.required,
+		Nullable:   i.nullable,
+		Min:        i.min,
+		Max:        i.max,
+		Positive:   i.positive,
+		Ranges:     i.rangeSpecs(),
+		Type:       integerType,
+		Big:        i.big,
+		Validators: i.validators.names(),
+		Default:    i.defaultValue,
+	}
+	if i.multipleOfValidation {
+		spec.MultipleOf = &i.multipleOf
+	}
+	return json.Marshal(spec)
 }
 
 // Integer is the constructor of an integer field
@@ -8,13 +8,18 @@ type IntRangeSpec struct {
 
 // IntegerFieldSpec is a type used for parsing an IntegerField
 type IntegerFieldSpec struct {
-	Name     string         `mapstructure:"name" json:"name"`
-	Type     fieldType      `json:"type"`
-	Required bool           `mapstructure:"required" json:"required,omitempty"`
-	Min      int            `mapstructure:"min" json:"min,omitempty"`
-	Max      int            `mapstructure:"max" json:"max,omitempty"`
-	Positive bool           `mapstructure:"positive" json:"positive,omitempty"`
-	Ranges   []IntRangeSpec `mapstructure:"ranges" json:"ranges,omitempty"`
+	Name       string         `mapstructure:"name" json:"name"`
+	Type       fieldType      `json:"type"`
+	Required   bool           `mapstructure:"required" json:"required,omitempty"`
+	Nullable   bool           `mapstructure:"nullable" json:"nullable,omitempty"`
+	Min        int            `mapstructure:"min" json:"min,omitempty"`
+	Max        int            `mapstructure:"max" json:"max,omitempty"`
+	Positive   bool           `mapstructure:"positive" json:"positive,omitempty"`
+	Ranges     []IntRangeSpec `mapstructure:"ranges" json:"ranges,omitempty"`
+	MultipleOf *int           `mapstructure:"multiple_of" json:"multiple_of,omitempty"`
+	Big        bool           `mapstructure:"big" json:"big,omitempty"`
+	Validators []string       `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{}    `mapstructure:"default" json:"default,omitempty"`
 }
 
 // NewInteger receives an IntegerFieldSpec and returns and IntegerField
@@ -26,9 +31,10 @@ func NewInteger(spec IntegerFieldSpec, minValidation, maxValidation, signValidat
 			end:   rangeSpec.End,
 		})
 	}
-	return &IntegerField{
+	field := &IntegerField{
 		name:            spec.Name,
 		required:        spec.Required,
+		nullable:        spec.Nullable,
 		min:             spec.Min,
 		minValidation:   minValidation,
 		max:             spec.Max,
@@ -37,5 +43,13 @@ func NewInteger(spec IntegerFieldSpec, minValidation, maxValidation, signValidat
 		positive:        spec.Positive,
 		rangeValidation: rangeValidation,
 		ranges:          ranges,
+		big:             spec.Big,
+		validators:      namedValidators(spec.Validators),
+		defaultValue:    spec.Default,
 	}
+	if spec.MultipleOf != nil {
+		// a multiple_of of zero is kept, so it is reported by Compile
+		field.MultipleOf(*spec.MultipleOf)
+	}
+	return field
 }
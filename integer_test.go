@@ -2,6 +2,7 @@ package vjson
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -226,6 +227,108 @@ func TestIntegerField_Validate(t *testing.T) {
 	})
 }
 
+func TestIntegerField_Exact(t *testing.T) {
+	t.Run("non_integral", func(t *testing.T) {
+		schema := NewSchema(Integer("foo"))
+		errs := schema.ValidateDetailed([]byte(`{"foo": 1.5}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "type", errs[0].Rule)
+		assert.Equal(t, "Value for foo should be an integer", errs[0].Message)
+
+		assert.Nil(t, schema.ValidateString(`{"foo": 1.0}`))
+		assert.Nil(t, schema.ValidateString(`{"foo": 1e3}`))
+		assert.NotNil(t, schema.ValidateString(`{"foo": 1e-3}`))
+		assert.NotNil(t, Integer("foo").Validate(1.5))
+	})
+	t.Run("large_exponent", func(t *testing.T) {
+		schema := NewSchema(Integer("foo").Big())
+		for _, input := range []string{`1e100000`, `1E-100000`, `1e99999999999999999999`} {
+			errs := schema.ValidateDetailed([]byte(`{"foo": ` + input + `}`))
+			assert.Len(t, errs, 1)
+			assert.Equal(t, "exponent", errs[0].Rule)
+			assert.Equal(t, "Value for foo is out of range, its exponent should be at most 1000", errs[0].Message)
+		}
+		assert.Nil(t, schema.ValidateString(`{"foo": 1e1000}`))
+	})
+	t.Run("int64", func(t *testing.T) {
+		schema := NewSchema(Integer("id").Max(9007199254740992))
+		assert.Nil(t, schema.ValidateString(`{"id": 9007199254740992}`))
+		// 2^53 + 1 is equal to 2^53 in float64
+		errs := schema.ValidateDetailed([]byte(`{"id": 9007199254740993}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "max", errs[0].Rule)
+
+		schema = NewSchema(Integer("id"))
+		assert.Nil(t, schema.ValidateString(`{"id": -9223372036854775808}`))
+		errs = schema.ValidateDetailed([]byte(`{"id": 9223372036854775808}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "Value for id should be a 64-bit integer", errs[0].Message)
+	})
+	t.Run("big", func(t *testing.T) {
+		schema := NewSchema(Integer("id").Big().Positive())
+		assert.Nil(t, schema.ValidateString(`{"id": 18446744073709551616}`))
+		errs := schema.ValidateDetailed([]byte(`{"id": -18446744073709551616}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "positive", errs[0].Rule)
+		assert.Equal(t, "-18446744073709551616", errs[0].Actual.(fmt.Stringer).String())
+
+		assert.Nil(t, Integer("id").Big().Validate(json.Number("18446744073709551616")))
+		assert.Nil(t, Integer("id").Big().Validate(uint64(18446744073709551615)))
+		assert.NotNil(t, Integer("id").Validate(uint64(18446744073709551615)))
+	})
+	t.Run("multiple_of", func(t *testing.T) {
+		field := Integer("foo").MultipleOf(5)
+		assert.Nil(t, field.Validate(10))
+		assert.Nil(t, field.Validate(-15))
+		err := field.Validate(12)
+		assert.NotNil(t, err)
+		assert.Equal(t, "multiple_of", toValidationErrors(err)[0].Rule)
+
+		_, err = NewSchema(Integer("foo").MultipleOf(0)).Compile()
+		assert.NotNil(t, err)
+
+		// an invalid multiple_of is not treated as unconstrained if the schema is not compiled
+		for _, multipleOf := range []int{0, -5} {
+			errs := toValidationErrors(Integer("foo").MultipleOf(multipleOf).Validate(10))
+			assert.Len(t, errs, 1)
+			assert.Equal(t, "multiple_of", errs[0].Rule)
+			assert.Equal(t, "multiple_of of integer field foo should be positive", errs[0].Message)
+		}
+	})
+	t.Run("spec", func(t *testing.T) {
+		schema, err := ReadFromString(`{"fields": [{"name": "id", "type": "integer", "big": true, "multiple_of": 2}]}`)
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`{"id": 36893488147419103232}`))
+		assert.NotNil(t, schema.ValidateString(`{"id": 36893488147419103233}`))
+
+		content, err := json.Marshal(schema)
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `"multiple_of":2,"big":true`)
+
+		schema, err = ReadFromString(`{"fields": [{"name": "id", "type": "integer", "multiple_of": 0}]}`)
+		assert.Nil(t, err)
+		_, err = schema.Compile()
+		assert.NotNil(t, err)
+		assert.NotNil(t, schema.ValidateString(`{"id": 10}`))
+
+		content, err = json.Marshal(Integer("id").MultipleOf(0))
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `"multiple_of":0`)
+	})
+	t.Run("json_schema", func(t *testing.T) {
+		content, err := NewSchema(Integer("foo").MultipleOf(3)).ToJSONSchema()
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `"multipleOf":3`)
+
+		schema, err := FromJSONSchema(content)
+		assert.Nil(t, err)
+		assert.NotNil(t, schema.ValidateString(`{"foo": 4}`))
+
+		_, err = FromJSONSchema([]byte(`{"type": "object", "properties": {"foo": {"type": "integer", "multipleOf": 0.5}}}`))
+		assert.NotNil(t, err)
+	})
+}
+
 func TestIntegerField_MarshalJSON(t *testing.T) {
 	field := Integer("foo").Range(10, 20)
 	b, err := json.Marshal(field)
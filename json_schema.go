@@ -0,0 +1,927 @@
+package vjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"math"
+	"strings"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	// jsonSchemaDefinitions is the prefix of JSON Schema refs to definitions of the document.
+	jsonSchemaDefinitions = "#/$defs/"
+)
+
+// jsonSchemaAnnotations are JSON Schema keywords which do not affect validation, so they are ignored while importing.
+// default is imported as the default value of a field, and it is ignored in other places.
+var jsonSchemaAnnotations = map[string]struct{}{
+	"default":     {},
+	"$schema":     {},
+	"$id":         {},
+	"$comment":    {},
+	"title":       {},
+	"description": {},
+	"examples":    {},
+	"deprecated":  {},
+	"readOnly":    {},
+	"writeOnly":   {},
+	"format":      {},
+}
+
+// jsonSchemaProperty is a property of an exported JSON Schema object.
+type jsonSchemaProperty struct {
+	name   string
+	schema map[string]interface{}
+}
+
+// jsonSchemaProperties keeps the order of schema fields in an exported JSON Schema.
+type jsonSchemaProperties []jsonSchemaProperty
+
+func (p jsonSchemaProperties) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for index, property := range p {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		name, err := json.Marshal(property.name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(property.schema)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(schema)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// ToJSONSchema exports the schema as a JSON Schema (draft 2020-12) document.
+// it returns an error if a field of the schema can not be represented in JSON Schema.
+func (s Schema) ToJSONSchema() ([]byte, error) {
+	document, err := s.toJSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	document["$schema"] = jsonSchemaDraft
+	if len(s.definitions) > 0 {
+		definitions, err := s.definitionsToJSONSchema()
+		if err != nil {
+			return nil, err
+		}
+		document["$defs"] = definitions
+	}
+	return json.Marshal(document)
+}
+
+func (s *Schema) definitionsToJSONSchema() (map[string]interface{}, error) {
+	var result error
+	definitions := make(map[string]interface{}, len(s.definitions))
+	for _, name := range s.definitionNames() {
+		definition, err := fieldToJSONSchema(s.definitions[name])
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not export definition %s", name))
+			continue
+		}
+		definitions[name] = definition
+	}
+	if result != nil {
+		return nil, result
+	}
+	return definitions, nil
+}
+
+func (s *Schema) toJSONSchema() (map[string]interface{}, error) {
+	if s.root != nil {
+		document, err := fieldToJSONSchema(s.root)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not export root field")
+		}
+		err = s.rulesToJSONSchema(document)
+		if err != nil {
+			return nil, err
+		}
+		return document, nil
+	}
+
+	var result error
+	properties := make(jsonSchemaProperties, 0, len(s.Fields))
+	required := make([]string, 0)
+	for _, field := range s.Fields {
+		property, err := fieldToJSONSchema(field)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not export field %s", field.GetName()))
+			continue
+		}
+		properties = append(properties, jsonSchemaProperty{name: field.GetName(), schema: property})
+		if isRequired(field) {
+			required = append(required, field.GetName())
+		}
+	}
+
+	document := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		document["required"] = required
+	}
+	switch s.additionalProperties {
+	case additionalPropertiesAllow:
+		document["additionalProperties"] = true
+	case additionalPropertiesDeny:
+		document["additionalProperties"] = false
+	case additionalPropertiesValidate:
+		additional, err := fieldToJSONSchema(s.additionalField)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrap(err, "could not export additional properties field"))
+		} else {
+			document["additionalProperties"] = additional
+		}
+	}
+	err := s.rulesToJSONSchema(document)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	if result != nil {
+		return nil, result
+	}
+	return document, nil
+}
+
+// rulesToJSONSchema adds conditionals of the schema to allOf keyword of a JSON Schema as if, then and else keywords,
+// and dependencies as dependentRequired keyword. comparisons can not be represented in JSON Schema.
+func (s *Schema) rulesToJSONSchema(document map[string]interface{}) error {
+	var result error
+	if len(s.comparisons) > 0 {
+		result = multierror.Append(result, errors.Errorf("comparisons can not be represented in JSON Schema"))
+	}
+	allOf, _ := document["allOf"].([]interface{})
+	for index, conditional := range s.conditionals {
+		rule := make(map[string]interface{})
+		keywords := []string{"if", "then", "else"}
+		for keywordIndex, schema := range []*Schema{&conditional.condition, conditional.then, conditional.otherwise} {
+			if schema == nil {
+				continue
+			}
+			exported, err := schema.toJSONSchema()
+			if err != nil {
+				result = multierror.Append(result, errors.Wrapf(err, "could not export %s schema of condition %d", keywords[keywordIndex], index))
+				continue
+			}
+			rule[keywords[keywordIndex]] = exported
+		}
+		allOf = append(allOf, rule)
+	}
+	if len(allOf) > 0 {
+		document["allOf"] = allOf
+	}
+	if dependencies := s.dependenciesSpec(); dependencies != nil {
+		document["dependentRequired"] = dependencies
+	}
+	return result
+}
+
+func isRequired(field Field) bool {
+	switch f := field.(type) {
+	case *IntegerField:
+		return f.required
+	case *FloatField:
+		return f.required
+	case *StringField:
+		return f.required
+	case *BooleanField:
+		return f.required
+	case *ArrayField:
+		return f.required
+	case *ObjectField:
+		return f.required
+	case *CombinatorField:
+		return f.required
+	case *RefField:
+		return f.required
+	case *DecimalField:
+		return f.required
+	}
+	return false
+}
+
+// jsonSchemaType returns the type keyword of a field, which also accepts null if the field is nullable.
+func jsonSchemaType(name string, nullable bool) interface{} {
+	if nullable {
+		return []string{name, "null"}
+	}
+	return name
+}
+
+// fieldToJSONSchema returns the JSON Schema of a field, with its default value. custom validators are Go functions,
+// so fields which have them can not be represented.
+func fieldToJSONSchema(field Field) (map[string]interface{}, error) {
+	if custom, ok := field.(customValidated); ok && len(custom.getValidators()) > 0 {
+		return nil, errors.Errorf("custom validators %s can not be represented in JSON Schema", strings.Join(custom.getValidators().names(), ", "))
+	}
+	schema, err := fieldTypeToJSONSchema(field)
+	if err != nil {
+		return nil, err
+	}
+	if defaulted, ok := field.(defaultedField); ok {
+		if value, found := defaulted.getDefault(); found {
+			schema["default"] = value
+		}
+	}
+	return schema, nil
+}
+
+func fieldTypeToJSONSchema(field Field) (map[string]interface{}, error) {
+	switch f := field.(type) {
+	case *IntegerField:
+		schema := map[string]interface{}{"type": jsonSchemaType("integer", f.nullable)}
+		var minimum, maximum *float64
+		if f.minValidation {
+			minimum = floatPointer(float64(f.min))
+		}
+		if f.maxValidation {
+			maximum = floatPointer(float64(f.max))
+		}
+		setNumberBounds(schema, minimum, maximum, f.signValidation, f.positive)
+		if f.rangeValidation {
+			ranges := make([]interface{}, 0, len(f.ranges))
+			for _, r := range f.ranges {
+				ranges = append(ranges, map[string]interface{}{"minimum": r.start, "maximum": r.end})
+			}
+			schema["anyOf"] = ranges
+		}
+		if f.multipleOfValidation {
+			schema["multipleOf"] = f.multipleOf
+		}
+		return schema, nil
+	case *FloatField:
+		schema := map[string]interface{}{"type": jsonSchemaType("number", f.nullable)}
+		var minimum, maximum *float64
+		if f.minValidation {
+			minimum = floatPointer(f.min)
+		}
+		if f.maxValidation {
+			maximum = floatPointer(f.max)
+		}
+		setNumberBounds(schema, minimum, maximum, f.signValidation, f.positive)
+		if f.rangeValidation {
+			ranges := make([]interface{}, 0, len(f.ranges))
+			for _, r := range f.ranges {
+				ranges = append(ranges, map[string]interface{}{"minimum": r.start, "maximum": r.end})
+			}
+			schema["anyOf"] = ranges
+		}
+		return schema, nil
+	case *DecimalField:
+		if f.precisionValidation {
+			return nil, errors.Errorf("precision of decimal field %s can not be represented", f.name)
+		}
+		if f.allowStrings {
+			return nil, errors.Errorf("numeric strings of decimal field %s can not be represented", f.name)
+		}
+		schema := map[string]interface{}{"type": jsonSchemaType("number", f.nullable)}
+		if f.minValidation {
+			schema["minimum"] = json.Number(f.min)
+		}
+		if f.maxValidation {
+			schema["maximum"] = json.Number(f.max)
+		}
+		if f.rangeValidation {
+			ranges := make([]interface{}, 0, len(f.ranges))
+			for _, r := range f.ranges {
+				ranges = append(ranges, map[string]interface{}{"minimum": json.Number(r.start), "maximum": json.Number(r.end)})
+			}
+			schema["anyOf"] = ranges
+		}
+		if f.scaleValidation && f.scale > 0 {
+			schema["multipleOf"] = json.Number("0." + strings.Repeat("0", f.scale-1) + "1")
+		} else if f.scaleValidation {
+			schema["multipleOf"] = 1
+		}
+		return schema, nil
+	case *StringField:
+		schema := map[string]interface{}{"type": jsonSchemaType("string", f.nullable)}
+		if f.validateMinLength {
+			schema["minLength"] = f.minLength
+		}
+		if f.validateMaxLength {
+			schema["maxLength"] = f.maxLength
+		}
+		if f.validateFormat {
+			schema["pattern"] = f.format
+		}
+		if f.validateChoices {
+			choices := make([]interface{}, 0, len(f.choices)+1)
+			for _, choice := range f.choices {
+				choices = append(choices, choice)
+			}
+			if f.nullable {
+				choices = append(choices, nil)
+			}
+			schema["enum"] = choices
+		}
+		return schema, nil
+	case *BooleanField:
+		schema := map[string]interface{}{"type": jsonSchemaType("boolean", f.nullable)}
+		if f.valueValidation {
+			if f.nullable {
+				schema["enum"] = []interface{}{f.value, nil}
+			} else {
+				schema["const"] = f.value
+			}
+		}
+		return schema, nil
+	case *NullField:
+		return map[string]interface{}{"type": "null"}, nil
+	case *ArrayField:
+		schema := map[string]interface{}{"type": jsonSchemaType("array", f.nullable)}
+		items, err := fieldToJSONSchema(f.items)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not export items field")
+		}
+		schema["items"] = items
+		if f.minLengthValidation {
+			schema["minItems"] = f.minLength
+		}
+		if f.maxLengthValidation {
+			schema["maxItems"] = f.maxLength
+		}
+		return schema, nil
+	case *ObjectField:
+		if len(f.schema.definitions) > 0 {
+			return nil, errors.Errorf("definitions of sub-schemas can not be represented, define them in the top-level schema")
+		}
+		schema, err := f.schema.toJSONSchema()
+		if err != nil {
+			return nil, err
+		}
+		if f.schema.root != nil {
+			schema = map[string]interface{}{"allOf": []interface{}{schema}}
+		}
+		schema["type"] = jsonSchemaType("object", f.nullable)
+		return schema, nil
+	case *RefField:
+		schema := map[string]interface{}{"$ref": jsonSchemaDefinitions + escapePointerToken(f.ref)}
+		if f.nullable {
+			return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}, nil
+		}
+		return schema, nil
+	case *CombinatorField:
+		branches := make([]interface{}, 0, len(f.fields))
+		for index, branch := range f.fields {
+			branchSchema, err := fieldToJSONSchema(branch)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not export field %d of %s field", index, f.kind)
+			}
+			branches = append(branches, branchSchema)
+		}
+		switch f.kind {
+		case oneOfType:
+			return map[string]interface{}{"oneOf": branches}, nil
+		case anyOfType:
+			return map[string]interface{}{"anyOf": branches}, nil
+		case allOfType:
+			return map[string]interface{}{"allOf": branches}, nil
+		case notType:
+			if len(branches) != 1 {
+				return nil, errors.Errorf("not field should have exactly one field")
+			}
+			return map[string]interface{}{"not": branches[0]}, nil
+		}
+	case nil:
+		return nil, errMissingField
+	}
+	return nil, errors.Errorf("field type %T can not be represented in JSON Schema", field)
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}
+
+// setNumberBounds sets minimum and maximum keywords of a number schema. sign validation is a bound on zero.
+func setNumberBounds(schema map[string]interface{}, minimum, maximum *float64, signValidation, positive bool) {
+	if signValidation && positive && (minimum == nil || *minimum < 0) {
+		minimum = floatPointer(0)
+	}
+	if signValidation && !positive && (maximum == nil || *maximum > 0) {
+		maximum = floatPointer(0)
+	}
+	if minimum != nil {
+		schema["minimum"] = *minimum
+	}
+	if maximum != nil {
+		schema["maximum"] = *maximum
+	}
+}
+
+// FromJSONSchema parses a JSON Schema (draft 2020-12) document of an object into a Schema.
+// it returns an error which lists every keyword that can not be represented by vjson fields.
+func FromJSONSchema(input []byte) (*Schema, error) {
+	if !gjson.ValidBytes(input) {
+		return nil, errors.Errorf("could not parse json schema input.")
+	}
+	var importer jsonSchemaImporter
+	schema := importer.schema(gjson.ParseBytes(input), "#")
+	if importer.err != nil {
+		return nil, importer.err
+	}
+	return &schema, nil
+}
+
+// jsonSchemaImporter converts JSON Schema documents to fields and collects the problems it finds.
+type jsonSchemaImporter struct {
+	err error
+	// definitions are the names of $defs of the document, which can be used by $ref keywords.
+	definitions map[string]struct{}
+}
+
+func (j *jsonSchemaImporter) fail(path string, format string, args ...interface{}) {
+	j.err = multierror.Append(j.err, errors.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+// unsupported reports keywords of a JSON Schema which are not handled by the importer.
+func (j *jsonSchemaImporter) unsupported(document gjson.Result, path string, supported ...string) {
+	document.ForEach(func(key, _ gjson.Result) bool {
+		keyword := key.String()
+		if _, ok := jsonSchemaAnnotations[keyword]; ok {
+			return true
+		}
+		if keyword == "$defs" && path == "#" {
+			// definitions of the document are imported by schema
+			return true
+		}
+		for _, s := range supported {
+			if s == keyword {
+				return true
+			}
+		}
+		j.fail(path, "keyword %s can not be represented", keyword)
+		return true
+	})
+}
+
+func (j *jsonSchemaImporter) schema(document gjson.Result, path string) Schema {
+	if !document.IsObject() {
+		j.fail(path, "schema should be an object")
+		return Schema{}
+	}
+
+	defs := document.Get("$defs")
+	j.definitions = make(map[string]struct{})
+	defs.ForEach(func(key, _ gjson.Result) bool {
+		j.definitions[key.String()] = struct{}{}
+		return true
+	})
+	definitions := make(map[string]Field)
+	defs.ForEach(func(key, value gjson.Result) bool {
+		name := key.String()
+		field := j.field(name, value, path+"/$defs/"+escapePointerToken(name))
+		if field != nil {
+			definitions[name] = field
+		}
+		return true
+	})
+
+	var schema Schema
+	typeName, nullable := j.typeName(document, path)
+	if (typeName != "" && typeName != "object") || nullable || document.Get("$ref").Exists() {
+		// documents which are not always an object are validated with a root field
+		field := j.field(rootFieldName, document, path)
+		if field == nil {
+			return Schema{}
+		}
+		schema = NewRootSchema(field)
+	} else {
+		schema = j.objectSchema(document, path)
+	}
+	if len(definitions) > 0 {
+		schema.definitions = definitions
+		schema.resolveRefs()
+	}
+	return schema
+}
+
+func (j *jsonSchemaImporter) objectSchema(document gjson.Result, path string) Schema {
+	j.unsupported(document, path, "type", "properties", "required", "additionalProperties", "if", "then", "else", "allOf", "dependentRequired")
+
+	required := make(map[string]bool)
+	for _, name := range document.Get("required").Array() {
+		required[name.String()] = true
+	}
+
+	var schema Schema
+	schema.Fields = make([]Field, 0)
+	document.Get("properties").ForEach(func(key, value gjson.Result) bool {
+		name := key.String()
+		field := j.field(name, value, path+"/properties/"+escapePointerToken(name))
+		if field != nil {
+			setRequired(field, required[name])
+			schema.Fields = append(schema.Fields, field)
+		}
+		return true
+	})
+
+	additional := document.Get("additionalProperties")
+	switch {
+	case !additional.Exists():
+	case additional.Type == gjson.True:
+		schema.additionalProperties = additionalPropertiesAllow
+	case additional.Type == gjson.False:
+		schema.additionalProperties = additionalPropertiesDeny
+	default:
+		field := j.field("additional", additional, path+"/additionalProperties")
+		if field != nil {
+			schema.additionalProperties = additionalPropertiesValidate
+			schema.additionalField = field
+		}
+	}
+
+	if conditional := j.conditional(document, path); conditional != nil {
+		schema.conditionals = append(schema.conditionals, conditional)
+	}
+	for index, rule := range document.Get("allOf").Array() {
+		rulePath := fmt.Sprintf("%s/allOf/%d", path, index)
+		if !rule.Get("if").Exists() {
+			j.fail(rulePath, "allOf of an object can only contain if, then and else keywords")
+			continue
+		}
+		j.unsupported(rule, rulePath, "if", "then", "else")
+		if conditional := j.conditional(rule, rulePath); conditional != nil {
+			schema.conditionals = append(schema.conditionals, conditional)
+		}
+	}
+	document.Get("dependentRequired").ForEach(func(key, value gjson.Result) bool {
+		var required []string
+		for _, name := range value.Array() {
+			required = append(required, name.String())
+		}
+		schema.dependencies = append(schema.dependencies, dependency{field: key.String(), required: required})
+		return true
+	})
+	return schema
+}
+
+// conditional imports if, then and else keywords of a JSON Schema. it returns nil if there is no if keyword.
+func (j *jsonSchemaImporter) conditional(document gjson.Result, path string) *Conditional {
+	condition := document.Get("if")
+	if !condition.Exists() {
+		if document.Get("then").Exists() || document.Get("else").Exists() {
+			j.fail(path, "then and else keywords without if keyword can not be represented")
+		}
+		return nil
+	}
+	conditional := If(j.objectSchema(condition, path+"/if"))
+	if then := document.Get("then"); then.Exists() {
+		conditional.Then(j.objectSchema(then, path+"/then"))
+	}
+	if otherwise := document.Get("else"); otherwise.Exists() {
+		conditional.Else(j.objectSchema(otherwise, path+"/else"))
+	}
+	return conditional
+}
+
+func setRequired(field Field, required bool) {
+	if !required {
+		return
+	}
+	switch f := field.(type) {
+	case *IntegerField:
+		f.Required()
+	case *FloatField:
+		f.Required()
+	case *StringField:
+		f.Required()
+	case *BooleanField:
+		f.Required()
+	case *ArrayField:
+		f.Required()
+	case *ObjectField:
+		f.Required()
+	case *CombinatorField:
+		f.Required()
+	case *RefField:
+		f.Required()
+	case *DecimalField:
+		f.Required()
+	}
+}
+
+// typeName returns the type keyword of a JSON Schema, and whether it also accepts null.
+func (j *jsonSchemaImporter) typeName(document gjson.Result, path string) (string, bool) {
+	typeValue := document.Get("type")
+	if !typeValue.Exists() {
+		return "", false
+	}
+	if typeValue.Type == gjson.String {
+		return typeValue.String(), false
+	}
+	if typeValue.IsArray() {
+		nullable := false
+		var types []string
+		for _, t := range typeValue.Array() {
+			if t.String() == "null" {
+				nullable = true
+				continue
+			}
+			types = append(types, t.String())
+		}
+		if len(types) == 0 {
+			return "null", false
+		}
+		if len(types) == 1 {
+			return types[0], nullable
+		}
+		j.fail(path, "multiple types %v can not be represented, use oneOf instead", types)
+		return "", false
+	}
+	j.fail(path, "invalid format for type keyword")
+	return "", false
+}
+
+// field returns the field of a JSON Schema, with the value of its default keyword.
+func (j *jsonSchemaImporter) field(name string, document gjson.Result, path string) Field {
+	field := j.fieldType(name, document, path)
+	value := document.Get("default")
+	if field != nil && value.Exists() && value.Type != gjson.Null {
+		setDefault(field, value.Value())
+	}
+	return field
+}
+
+func (j *jsonSchemaImporter) fieldType(name string, document gjson.Result, path string) Field {
+	if !document.IsObject() {
+		j.fail(path, "boolean schemas can not be represented")
+		return nil
+	}
+	if document.Get("$ref").Exists() {
+		return j.refField(name, document, path)
+	}
+	typeName, nullable := j.typeName(document, path)
+	switch typeName {
+	case "integer":
+		return j.integerField(name, document, path, nullable)
+	case "number":
+		return j.floatField(name, document, path, nullable)
+	case "string":
+		return j.stringField(name, document, path, nullable)
+	case "boolean":
+		return j.booleanField(name, document, path, nullable)
+	case "null":
+		j.unsupported(document, path, "type")
+		return Null(name)
+	case "array":
+		return j.arrayField(name, document, path, nullable)
+	case "object":
+		field := Object(name, j.objectSchema(document, path))
+		field.nullable = nullable
+		return field
+	case "":
+		return j.combinatorField(name, document, path)
+	}
+	j.fail(path, "type %s can not be represented", typeName)
+	return nil
+}
+
+func (j *jsonSchemaImporter) refField(name string, document gjson.Result, path string) Field {
+	j.unsupported(document, path, "$ref")
+	ref := document.Get("$ref").String()
+	if !strings.HasPrefix(ref, jsonSchemaDefinitions) {
+		j.fail(path, "$ref %s can not be represented, only refs to $defs of the document are supported", ref)
+		return nil
+	}
+	definition := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, jsonSchemaDefinitions))
+	if _, found := j.definitions[definition]; !found {
+		j.fail(path, "definition %s of $ref is not found", definition)
+		return nil
+	}
+	return Ref(name, definition)
+}
+
+// enum returns values of enum keyword without null, and whether null is one of them.
+func enum(document gjson.Result) ([]gjson.Result, bool) {
+	var values []gjson.Result
+	hasNull := false
+	for _, value := range document.Get("enum").Array() {
+		if value.Type == gjson.Null {
+			hasNull = true
+			continue
+		}
+		values = append(values, value)
+	}
+	return values, hasNull
+}
+
+func isBool(value gjson.Result) bool {
+	return value.Type == gjson.True || value.Type == gjson.False
+}
+
+func integerValue(value gjson.Result) (int, bool) {
+	if value.Type != gjson.Number || value.Num != math.Trunc(value.Num) {
+		return 0, false
+	}
+	return int(value.Num), true
+}
+
+func (j *jsonSchemaImporter) integerField(name string, document gjson.Result, path string, nullable bool) Field {
+	j.unsupported(document, path, "type", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "anyOf", "multipleOf")
+	field := Integer(name)
+	field.nullable = nullable
+	if minimum := document.Get("minimum"); minimum.Exists() {
+		value, ok := integerValue(minimum)
+		if !ok {
+			j.fail(path, "minimum of an integer should be an integer")
+		}
+		field.Min(value)
+	}
+	if maximum := document.Get("maximum"); maximum.Exists() {
+		value, ok := integerValue(maximum)
+		if !ok {
+			j.fail(path, "maximum of an integer should be an integer")
+		}
+		field.Max(value)
+	}
+	if minimum := document.Get("exclusiveMinimum"); minimum.Exists() {
+		value, ok := integerValue(minimum)
+		if !ok {
+			j.fail(path, "exclusiveMinimum of an integer should be an integer")
+		}
+		if !field.minValidation || value+1 > field.min {
+			field.Min(value + 1)
+		}
+	}
+	if maximum := document.Get("exclusiveMaximum"); maximum.Exists() {
+		value, ok := integerValue(maximum)
+		if !ok {
+			j.fail(path, "exclusiveMaximum of an integer should be an integer")
+		}
+		if !field.maxValidation || value-1 < field.max {
+			field.Max(value - 1)
+		}
+	}
+	for index, r := range document.Get("anyOf").Array() {
+		rangePath := fmt.Sprintf("%s/anyOf/%d", path, index)
+		j.unsupported(r, rangePath, "minimum", "maximum")
+		start, startOK := integerValue(r.Get("minimum"))
+		end, endOK := integerValue(r.Get("maximum"))
+		if !startOK || !endOK {
+			j.fail(rangePath, "anyOf of an integer should only contain integer minimum and maximum ranges")
+			continue
+		}
+		field.Range(start, end)
+	}
+	if multipleOf := document.Get("multipleOf"); multipleOf.Exists() {
+		value, ok := integerValue(multipleOf)
+		if !ok || value <= 0 {
+			j.fail(path, "multipleOf of an integer should be a positive integer")
+		}
+		field.MultipleOf(value)
+	}
+	return field
+}
+
+func (j *jsonSchemaImporter) floatField(name string, document gjson.Result, path string, nullable bool) Field {
+	j.unsupported(document, path, "type", "minimum", "maximum", "anyOf")
+	field := Float(name)
+	field.nullable = nullable
+	if minimum := document.Get("minimum"); minimum.Exists() {
+		if minimum.Type != gjson.Number {
+			j.fail(path, "minimum should be a number")
+		}
+		field.Min(minimum.Num)
+	}
+	if maximum := document.Get("maximum"); maximum.Exists() {
+		if maximum.Type != gjson.Number {
+			j.fail(path, "maximum should be a number")
+		}
+		field.Max(maximum.Num)
+	}
+	for index, r := range document.Get("anyOf").Array() {
+		rangePath := fmt.Sprintf("%s/anyOf/%d", path, index)
+		j.unsupported(r, rangePath, "minimum", "maximum")
+		start, end := r.Get("minimum"), r.Get("maximum")
+		if start.Type != gjson.Number || end.Type != gjson.Number {
+			j.fail(rangePath, "anyOf of a number should only contain minimum and maximum ranges")
+			continue
+		}
+		field.Range(start.Num, end.Num)
+	}
+	return field
+}
+
+func (j *jsonSchemaImporter) stringField(name string, document gjson.Result, path string, nullable bool) Field {
+	j.unsupported(document, path, "type", "minLength", "maxLength", "pattern", "enum")
+	field := String(name)
+	field.nullable = nullable
+	if minLength := document.Get("minLength"); minLength.Exists() {
+		field.MinLength(int(minLength.Int()))
+	}
+	if maxLength := document.Get("maxLength"); maxLength.Exists() {
+		field.MaxLength(int(maxLength.Int()))
+	}
+	if pattern := document.Get("pattern"); pattern.Exists() {
+		field.Format(pattern.String())
+	}
+	if document.Get("enum").Exists() {
+		values, _ := enum(document)
+		choices := make([]string, 0, len(values))
+		for _, value := range values {
+			if value.Type != gjson.String {
+				j.fail(path, "enum of a string should only contain strings")
+				continue
+			}
+			choices = append(choices, value.String())
+		}
+		field.Choices(choices...)
+	}
+	return field
+}
+
+func (j *jsonSchemaImporter) booleanField(name string, document gjson.Result, path string, nullable bool) Field {
+	j.unsupported(document, path, "type", "const", "enum")
+	field := Boolean(name)
+	field.nullable = nullable
+	if value := document.Get("const"); value.Exists() {
+		if !isBool(value) {
+			j.fail(path, "const of a boolean should be a boolean")
+		}
+		field.ShouldBe(value.Bool())
+	}
+	if document.Get("enum").Exists() {
+		values, _ := enum(document)
+		if len(values) != 1 || !isBool(values[0]) {
+			j.fail(path, "enum of a boolean should contain a single boolean")
+		} else {
+			field.ShouldBe(values[0].Bool())
+		}
+	}
+	return field
+}
+
+func (j *jsonSchemaImporter) arrayField(name string, document gjson.Result, path string, nullable bool) Field {
+	j.unsupported(document, path, "type", "items", "minItems", "maxItems")
+	items := document.Get("items")
+	if !items.Exists() {
+		j.fail(path, "array without items keyword can not be represented")
+		return nil
+	}
+	itemField := j.field(name, items, path+"/items")
+	if itemField == nil {
+		return nil
+	}
+	field := Array(name, itemField)
+	field.nullable = nullable
+	if minItems := document.Get("minItems"); minItems.Exists() {
+		field.MinLength(int(minItems.Int()))
+	}
+	if maxItems := document.Get("maxItems"); maxItems.Exists() {
+		field.MaxLength(int(maxItems.Int()))
+	}
+	return field
+}
+
+func (j *jsonSchemaImporter) combinatorField(name string, document gjson.Result, path string) Field {
+	keywords := map[string]fieldType{"oneOf": oneOfType, "anyOf": anyOfType, "allOf": allOfType, "not": notType}
+	var found []string
+	for keyword := range keywords {
+		if document.Get(keyword).Exists() {
+			found = append(found, keyword)
+		}
+	}
+	if len(found) != 1 {
+		j.fail(path, "schema should have a type or exactly one of oneOf, anyOf, allOf and not keywords")
+		return nil
+	}
+	keyword := found[0]
+	j.unsupported(document, path, keyword)
+
+	if keyword == "not" {
+		field := j.field(name, document.Get(keyword), path+"/not")
+		if field == nil {
+			return nil
+		}
+		return Not(name, field)
+	}
+
+	var fields []Field
+	for index, branch := range document.Get(keyword).Array() {
+		field := j.field(name, branch, fmt.Sprintf("%s/%s/%d", path, keyword, index))
+		if field != nil {
+			fields = append(fields, field)
+		}
+	}
+	return &CombinatorField{
+		name:   name,
+		kind:   keywords[keyword],
+		fields: fields,
+	}
+}
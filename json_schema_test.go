@@ -0,0 +1,225 @@
+package vjson
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchema_ToJSONSchema(t *testing.T) {
+	schema := NewSchema(
+		Integer("age").Required().Min(18).Positive().Range(18, 30).Range(60, 90),
+		Float("score").Max(10.5).Negative(),
+		String("name").Required().MinLength(2).MaxLength(10).Format("^[a-z]+$"),
+		String("role").Choices("admin", "user").Nullable(),
+		Boolean("active").ShouldBe(true),
+		Null("deleted_at"),
+		Array("tags", String("tag")).MinLength(1).MaxLength(5),
+		Object("address", NewSchema(
+			String("city").Required(),
+		).Strict()),
+		OneOf("id", String("id"), Integer("id")),
+		Not("code", String("code").Choices("root")),
+	).AdditionalProperties(String("extra"))
+
+	document, err := schema.ToJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer", "minimum": 18, "anyOf": [{"minimum": 18, "maximum": 30}, {"minimum": 60, "maximum": 90}]},
+			"score": {"type": "number", "maximum": 0},
+			"name": {"type": "string", "minLength": 2, "maxLength": 10, "pattern": "^[a-z]+$"},
+			"role": {"type": ["string", "null"], "enum": ["admin", "user", null]},
+			"active": {"type": "boolean", "const": true},
+			"deleted_at": {"type": "null"},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "maxItems": 5},
+			"address": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"], "additionalProperties": false},
+			"id": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+			"code": {"not": {"type": "string", "enum": ["root"]}}
+		},
+		"required": ["age", "name"],
+		"additionalProperties": {"type": "string"}
+	}`, string(document))
+
+	// properties keep the order of schema fields
+	assert.Regexp(t, `"age".*"score".*"name".*"role".*"active"`, string(document))
+}
+
+func TestSchema_ToJSONSchemaRoot(t *testing.T) {
+	schema := NewRootSchema(Array("items", Object("item", NewSchema(Integer("id").Required()))).MaxLength(10))
+	document, err := schema.ToJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "array",
+		"maxItems": 10,
+		"items": {"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}
+	}`, string(document))
+
+	schema = NewSchema(Object("codes", NewRootSchema(String("code"))))
+	document, err = schema.ToJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {"codes": {"type": "object", "allOf": [{"type": "string"}]}}
+	}`, string(document))
+}
+
+func TestSchema_ToJSONSchemaDefinitions(t *testing.T) {
+	schema := NewSchema(
+		Ref("billing", "address").Required(),
+		Ref("shipping", "address").Nullable(),
+		Ref("tree", "node"),
+	).
+		Define("address", Object("address", NewSchema(String("city").Required()))).
+		Define("node", Object("node", NewSchema(Integer("value"), Array("children", Ref("child", "node")))))
+
+	document, err := schema.ToJSONSchema()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"billing": {"$ref": "#/$defs/address"},
+			"shipping": {"anyOf": [{"$ref": "#/$defs/address"}, {"type": "null"}]},
+			"tree": {"$ref": "#/$defs/node"}
+		},
+		"required": ["billing"],
+		"$defs": {
+			"address": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]},
+			"node": {"type": "object", "properties": {"value": {"type": "integer"}, "children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}
+		}
+	}`, string(document))
+
+	imported, err := FromJSONSchema(document)
+	assert.Nil(t, err)
+	assert.Nil(t, imported.ValidateString(`{"billing": {"city": "Paris"}, "tree": {"children": [{"value": 1, "children": []}]}}`))
+	errs := imported.ValidateDetailed([]byte(`{"billing": {}, "tree": {"children": [{"value": "a"}]}}`))
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "/billing/city", errs[0].Path)
+	assert.Equal(t, "/tree/children/0/value", errs[1].Path)
+
+	_, err = NewSchema(Object("inner", NewSchema(Ref("a", "b")).Define("b", String("b")))).ToJSONSchema()
+	assert.NotNil(t, err)
+}
+
+func TestSchema_ToJSONSchemaUnsupported(t *testing.T) {
+	t.Run("custom_validators", func(t *testing.T) {
+		schema := NewSchema(Array("cards", String("card").Custom("luhn", func(interface{}) error {
+			return nil
+		})))
+		_, err := schema.ToJSONSchema()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "custom validators luhn can not be represented in JSON Schema")
+
+		parsed, err := ReadFromString(`{"fields": [{"name": "card", "type": "string", "validators": ["luhn"]}]}`)
+		assert.Nil(t, err)
+		_, err = parsed.ToJSONSchema()
+		assert.NotNil(t, err)
+	})
+	t.Run("custom_types", func(t *testing.T) {
+		schema, err := ReadFromString(`{"fields": [{"name": "country", "type": "country_code"}]}`)
+		assert.Nil(t, err)
+		_, err = schema.ToJSONSchema()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "can not be represented in JSON Schema")
+	})
+}
+
+func TestFromJSONSchema(t *testing.T) {
+	t.Run("round_trip", func(t *testing.T) {
+		schema := NewSchema(
+			Integer("age").Required().Min(18).Max(99).Range(18, 30),
+			Float("score").Min(0.5).Nullable(),
+			String("name").Required().MinLength(2).Format("^[a-z]+$"),
+			String("role").Choices("admin", "user").Nullable(),
+			Boolean("active").ShouldBe(false).Nullable(),
+			Null("deleted_at"),
+			Array("tags", String("tag").MaxLength(3)).MinLength(1).Required(),
+			Object("address", NewSchema(
+				String("city").Required(),
+			)).Nullable(),
+			AnyOf("id", String("id"), Integer("id")).Required(),
+			Not("code", String("code").Choices("root")),
+		).Strict()
+
+		document, err := schema.ToJSONSchema()
+		assert.Nil(t, err)
+
+		imported, err := FromJSONSchema(document)
+		assert.Nil(t, err)
+		assert.Len(t, imported.Fields, 10)
+
+		reexported, err := imported.ToJSONSchema()
+		assert.Nil(t, err)
+		assert.JSONEq(t, string(document), string(reexported))
+	})
+	t.Run("validation", func(t *testing.T) {
+		schema, err := FromJSONSchema([]byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "Order",
+			"type": "object",
+			"properties": {
+				"quantity": {"type": "integer", "exclusiveMinimum": 0, "exclusiveMaximum": 100},
+				"email": {"type": "string", "format": "email", "pattern": "@"},
+				"items": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}, "required": ["sku"]}}
+			},
+			"required": ["quantity"]
+		}`))
+		assert.Nil(t, err)
+
+		assert.Nil(t, schema.ValidateString(`{"quantity": 1, "email": "a@b", "items": [{"sku": "x"}]}`))
+		assert.Nil(t, schema.ValidateString(`{"quantity": 99}`))
+
+		errs := schema.ValidateDetailed([]byte(`{"quantity": 100, "email": "ab", "items": [{}]}`))
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "/quantity", errs[0].Path)
+		assert.Equal(t, "max", errs[0].Rule)
+		assert.Equal(t, "/email", errs[1].Path)
+		assert.Equal(t, "/items/0/sku", errs[2].Path)
+
+		assert.NotNil(t, schema.ValidateString(`{"quantity": 0}`))
+		assert.NotNil(t, schema.ValidateString(`{}`))
+	})
+	t.Run("unsupported_keywords", func(t *testing.T) {
+		_, err := FromJSONSchema([]byte(`{
+			"type": "object",
+			"properties": {
+				"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+				"price": {"type": "number", "exclusiveMinimum": 0},
+				"value": {"type": ["string", "integer"]},
+				"ref": {"$ref": "other.json#/$defs/address"},
+				"missing": {"$ref": "#/$defs/address"}
+			},
+			"minProperties": 1
+		}`))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "#/properties/tags: keyword uniqueItems can not be represented")
+		assert.Contains(t, err.Error(), "#/properties/price: keyword exclusiveMinimum can not be represented")
+		assert.Contains(t, err.Error(), "#/properties/value: multiple types")
+		assert.Contains(t, err.Error(), "#/properties/ref: $ref other.json#/$defs/address can not be represented")
+		assert.Contains(t, err.Error(), "#/properties/missing: definition address of $ref is not found")
+		assert.Contains(t, err.Error(), "#: keyword minProperties can not be represented")
+	})
+	t.Run("root", func(t *testing.T) {
+		schema, err := FromJSONSchema([]byte(`{"type": "array", "items": {"type": "string"}, "maxItems": 2}`))
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`["a", "b"]`))
+		assert.NotNil(t, schema.ValidateString(`["a", "b", "c"]`))
+		assert.NotNil(t, schema.ValidateString(`{}`))
+
+		schema, err = FromJSONSchema([]byte(`{"type": ["object", "null"], "properties": {"name": {"type": "string"}}}`))
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`null`))
+		assert.NotNil(t, schema.ValidateString(`{"name": 1}`))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := FromJSONSchema([]byte(`{{`))
+		assert.NotNil(t, err)
+
+		_, err = FromJSONSchema([]byte(`{"type": "object", "properties": {"tags": {"type": "array"}}}`))
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,362 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// includeKey is the key of a schema spec which includes the schema of another file, e.g.
+// {"name": "address", "type": "object", "schema": {"$ref": "common/address.json"}}.
+const includeKey = "$ref"
+
+// Loader loads schema files by name, so schemas can include schemas of other files with $ref.
+// names are slash separated paths, and relative includes are resolved against the directory of the including file.
+type Loader interface {
+	Load(name string) ([]byte, error)
+}
+
+type dirLoader struct {
+	dir string
+}
+
+// DirLoader returns a Loader which reads files of the operating system, relative to dir.
+// files are read relative to the working directory if dir is empty.
+func DirLoader(dir string) Loader {
+	return dirLoader{dir: dir}
+}
+
+func (l dirLoader) Load(name string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(l.dir, filepath.FromSlash(name)))
+}
+
+type fsLoader struct {
+	fsys fs.FS
+}
+
+// FSLoader returns a Loader which reads files of a fs.FS, e.g. an embed.FS, so schemas can be embedded in binaries.
+func FSLoader(fsys fs.FS) Loader {
+	return fsLoader{fsys: fsys}
+}
+
+func (l fsLoader) Load(name string) ([]byte, error) {
+	return fs.ReadFile(l.fsys, name)
+}
+
+type mapLoader map[string][]byte
+
+// MapLoader returns a Loader which loads files from memory. the keys of files are the names of the files.
+func MapLoader(files map[string][]byte) Loader {
+	return mapLoader(files)
+}
+
+func (l mapLoader) Load(name string) ([]byte, error) {
+	content, found := l[name]
+	if !found {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return content, nil
+}
+
+// ReadFromLoader is for parsing a Schema from a file of a Loader.
+// the format of the file is detected by its extension, like ReadFromFile. the schema of an object field may include
+// the schema of another file with {"$ref": "path/to/file.json"}. included files may include other files too,
+// and their definitions are added to the definitions of the schema. cyclic includes are reported as an error.
+func ReadFromLoader(loader Loader, name string) (*Schema, error) {
+	content, err := loader.Load(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read file given, path: %s", name)
+	}
+	document, positions, err := decodeSchemaFile(name, content)
+	if err != nil {
+		// the file is parsed as it is, so its syntax error is reported like ReadFromBytes, ReadFromYAML and ReadFromTOML
+		return readSchemaFile(name, content)
+	}
+
+	includes := newSchemaIncludes(loader)
+	spec, err := includes.expandSchemaFile(name, document, positions)
+	if err != nil {
+		return nil, err
+	}
+
+	// errors of a json file are located only if it includes YAML or TOML files, whose definitions have positions
+	if isYAMLFile(name) || isTOMLFile(name) || includes.located {
+		return readFromDocument(spec, includes.positions)
+	}
+	input, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert schema spec to json")
+	}
+	return ReadFromBytes(input)
+}
+
+// ReadSpecFromLoader returns the spec of a schema file of a Loader in json format, with its includes expanded like
+// ReadFromLoader, so tools like vjson-gen can read schemas of every supported format. YAML and TOML files are converted
+// to json. it returns the error of ReadFromLoader if the schema is invalid.
+func ReadSpecFromLoader(loader Loader, name string) ([]byte, error) {
+	_, err := ReadFromLoader(loader, name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := loader.Load(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read file given, path: %s", name)
+	}
+	document, positions, err := decodeSchemaFile(name, content)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse schema file")
+	}
+	spec, err := newSchemaIncludes(loader).expandSchemaFile(name, document, positions)
+	if err != nil {
+		return nil, err
+	}
+	input, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert schema spec to json")
+	}
+	return input, nil
+}
+
+// expandSchemaFile replaces the includes of a decoded schema file with the schemas of the included files, and adds
+// the definitions of included files to the spec. positions are the positions of the file, and i.positions is set to
+// the positions of the expanded spec, so errors in included files point at the included files.
+func (i *schemaIncludes) expandSchemaFile(name string, document interface{}, positions documentPositions) (map[string]interface{}, error) {
+	i.positions = make(documentPositions, len(positions))
+	for pointer, position := range positions {
+		i.positions[pointer] = position
+	}
+	if positions == nil {
+		// json files have no positions
+		i.positions[""] = documentPosition{}
+	}
+	spec, err := i.expandFile(name, document, i.positions, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = i.addDefinitions(spec, i.positions)
+	if err != nil {
+		return nil, err
+	}
+	if len(i.definitions) > 0 {
+		spec["definitions"] = i.definitions
+	}
+	return spec, nil
+}
+
+// readSchemaFile parses the content of a schema file by its extension.
+func readSchemaFile(name string, content []byte) (*Schema, error) {
+	switch {
+	case isYAMLFile(name):
+		return ReadFromYAML(content)
+	case isTOMLFile(name):
+		return ReadFromTOML(content)
+	}
+	return ReadFromBytes(content)
+}
+
+// decodeSchemaFile decodes a schema file to the values of a json document by its extension.
+// numbers of json files are kept as json.Number, so they are not changed by converting the document back to json.
+func decodeSchemaFile(name string, content []byte) (interface{}, documentPositions, error) {
+	switch {
+	case isYAMLFile(name):
+		return yamlDocument(content)
+	case isTOMLFile(name):
+		document, err := tomlDocument(content)
+		if err != nil {
+			return nil, nil, err
+		}
+		return document, tomlPositions(content), nil
+	}
+	var document interface{}
+	err := decodeSpec(content, &document)
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, nil, nil
+}
+
+// schemaIncludes expands the includes of a schema file.
+type schemaIncludes struct {
+	loader Loader
+	// schemas are the expanded schema specs of loaded files, so a file which is included many times is loaded once.
+	schemas map[string]includedSchema
+	// definitions are the definitions of included files, which are moved to the top-level schema.
+	definitions map[string]interface{}
+	// positions are the positions of the expanded spec by its pointers. positions of included files have their names.
+	positions documentPositions
+	// located is true if an included file has positions, i.e. it is a YAML or TOML file.
+	located bool
+}
+
+// includedSchema is the expanded schema spec of an included file and its positions, which are relative to the spec.
+type includedSchema struct {
+	spec      map[string]interface{}
+	positions documentPositions
+}
+
+func newSchemaIncludes(loader Loader) *schemaIncludes {
+	return &schemaIncludes{
+		loader:      loader,
+		schemas:     make(map[string]includedSchema),
+		definitions: make(map[string]interface{}),
+	}
+}
+
+// expandFile expands the includes of the decoded schema spec of a file, and adds the positions of included files to
+// positions of the file. stack is the chain of files which include it.
+func (i *schemaIncludes) expandFile(name string, document interface{}, positions documentPositions, stack []string) (map[string]interface{}, error) {
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	spec, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("schema of file %s should be an object", name)
+	}
+	// names are cleaned, so a file is found in the stack however it is referred to
+	stack = append(stack, path.Clean(name))
+	err := i.expand(spec, name, "", positions, stack)
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// expand replaces the includes of schema specs in a value of a schema file with the schemas of the included files.
+// pointer is the JSON Pointer of the value in the file.
+func (i *schemaIncludes) expand(value interface{}, name, pointer string, positions documentPositions, stack []string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPointer := pointer + "/" + escapePointerToken(key)
+			if schema, ok := child.(map[string]interface{}); ok && key == "schema" {
+				if include, found := schema[includeKey]; found {
+					// other keys would be replaced by the included schema, so they are rejected instead of being dropped
+					if len(schema) > 1 {
+						return errors.Errorf("schema %s of file %s has other keys with %s key: %s", childPointer, name, includeKey, strings.Join(otherKeys(schema, includeKey), ", "))
+					}
+					included, err := i.include(include, name, stack)
+					if err != nil {
+						return err
+					}
+					v[key] = included.spec
+					for includedPointer, position := range included.positions {
+						positions[childPointer+includedPointer] = position
+					}
+					continue
+				}
+			}
+			err := i.expand(child, name, childPointer, positions, stack)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for index, child := range v {
+			err := i.expand(child, name, pointer+"/"+strconv.Itoa(index), positions, stack)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// otherKeys returns the sorted keys of a spec except key.
+func otherKeys(spec map[string]interface{}, key string) []string {
+	keys := make([]string, 0, len(spec))
+	for other := range spec {
+		if other != key {
+			keys = append(keys, other)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// include returns the expanded schema spec of an included file. its definitions are moved to i.definitions.
+func (i *schemaIncludes) include(include interface{}, name string, stack []string) (includedSchema, error) {
+	ref, ok := include.(string)
+	if !ok || ref == "" {
+		return includedSchema{}, errors.Errorf("invalid format for %s key in file %s", includeKey, name)
+	}
+	includedName := path.Clean(ref)
+	if !path.IsAbs(ref) {
+		includedName = path.Join(path.Dir(name), ref)
+	}
+
+	for _, including := range stack {
+		if including == includedName {
+			return includedSchema{}, errors.Errorf("cyclic schema include: %s", strings.Join(append(stack, includedName), " -> "))
+		}
+	}
+	if included, found := i.schemas[includedName]; found {
+		return included, nil
+	}
+
+	content, err := i.loader.Load(includedName)
+	if err != nil {
+		return includedSchema{}, errors.Wrapf(err, "could not read file %s included by %s", includedName, name)
+	}
+	document, positions, err := decodeSchemaFile(includedName, content)
+	if err != nil {
+		return includedSchema{}, errors.Wrapf(err, "could not parse file %s included by %s", includedName, name)
+	}
+	if positions != nil {
+		i.located = true
+	}
+	positions = filePositions(includedName, positions)
+	spec, err := i.expandFile(includedName, document, positions, stack)
+	if err != nil {
+		return includedSchema{}, err
+	}
+	err = i.addDefinitions(spec, positions)
+	if err != nil {
+		return includedSchema{}, errors.Wrapf(err, "could not include file %s", includedName)
+	}
+	included := includedSchema{spec: spec, positions: positions}
+	i.schemas[includedName] = included
+	return included, nil
+}
+
+// filePositions returns the positions of an included file with its name. a json file has no positions, so only its
+// name is kept.
+func filePositions(name string, positions documentPositions) documentPositions {
+	result := documentPositions{"": {file: name}}
+	for pointer, position := range positions {
+		position.file = name
+		result[pointer] = position
+	}
+	return result
+}
+
+// addDefinitions moves the definitions of a schema spec to i.definitions, so they are declared in the top-level schema.
+// a definition which is declared by many files should be the same in all of them. positions of the definitions are
+// moved to the top-level schema too.
+func (i *schemaIncludes) addDefinitions(spec map[string]interface{}, positions documentPositions) error {
+	definitions, _ := spec["definitions"].(map[string]interface{})
+	for definitionName, definition := range definitions {
+		existing, found := i.definitions[definitionName]
+		if found && !reflect.DeepEqual(existing, definition) {
+			return errors.Errorf("definition %s is declared differently in included files", definitionName)
+		}
+		i.definitions[definitionName] = definition
+		if found {
+			continue
+		}
+		prefix := "/definitions/" + escapePointerToken(definitionName)
+		for pointer, position := range positions {
+			if pointer == prefix || strings.HasPrefix(pointer, prefix+"/") {
+				i.positions[pointer] = position
+			}
+		}
+	}
+	delete(spec, "definitions")
+	return nil
+}
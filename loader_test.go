@@ -0,0 +1,236 @@
+package vjson
+
+import (
+	"embed"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"testing"
+)
+
+//go:embed test/include
+var includeFS embed.FS
+
+func TestReadFromFile_Include(t *testing.T) {
+	schema, err := ReadFromFile("test/include/order.json")
+	assert.Nil(t, err)
+
+	err = schema.ValidateString(`{"id": 1, "billing": {"city": "Paris", "country": {"code": "FR"}}, "total": 10.5}`)
+	assert.Nil(t, err)
+
+	errs := schema.ValidateDetailed([]byte(`{"id": 1, "billing": {"country": {"code": "FRA"}}, "shipping": {}, "total": -1}`))
+	assert.Len(t, errs, 4)
+	assert.Equal(t, "/billing/city", errs[0].Path)
+	assert.Equal(t, "/billing/country/code", errs[1].Path)
+	assert.Equal(t, "/shipping/city", errs[2].Path)
+	assert.Equal(t, "/total", errs[3].Path)
+}
+
+func TestReadFromLoader(t *testing.T) {
+	t.Run("fs", func(t *testing.T) {
+		fsys, err := fs.Sub(includeFS, "test/include")
+		assert.Nil(t, err)
+		schema, err := ReadFromLoader(FSLoader(fsys), "order.json")
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`{"id": 1, "billing": {"city": "Paris"}}`))
+		assert.NotNil(t, schema.ValidateString(`{"id": 1, "billing": {}}`))
+	})
+	t.Run("dir", func(t *testing.T) {
+		schema, err := ReadFromLoader(DirLoader("test/include"), "order.json")
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`{"id": 1, "billing": {"city": "Paris"}}`))
+	})
+	t.Run("map", func(t *testing.T) {
+		loader := MapLoader(map[string][]byte{
+			"schemas/user.yaml": []byte(`
+fields:
+  - name: name
+    type: string
+    required: true
+  - name: address
+    type: object
+    schema:
+      $ref: ../common/address.toml
+`),
+			"common/address.toml": []byte(`
+[[fields]]
+name = "zip"
+type = "integer"
+required = true
+`),
+		})
+		schema, err := ReadFromLoader(loader, "schemas/user.yaml")
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`{"name": "John", "address": {"zip": 75000}}`))
+		assert.NotNil(t, schema.ValidateString(`{"name": "John", "address": {}}`))
+	})
+	t.Run("same_definitions", func(t *testing.T) {
+		loader := MapLoader(map[string][]byte{
+			"a.json": []byte(`{
+				"definitions": {"money": {"type": "float"}},
+				"fields": [
+					{"name": "b", "type": "object", "schema": {"$ref": "b.json"}},
+					{"name": "c", "type": "object", "schema": {"$ref": "c.json"}}
+				]
+			}`),
+			"b.json": []byte(`{"definitions": {"money": {"type": "float"}}, "fields": [{"name": "price", "type": "ref", "ref": "money"}]}`),
+			"c.json": []byte(`{"definitions": {"money": {"type": "integer"}}, "fields": []}`),
+		})
+		_, err := ReadFromLoader(loader, "a.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "definition money is declared differently")
+	})
+	t.Run("cycle", func(t *testing.T) {
+		_, err := ReadFromFile("test/include/cycle.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "cyclic schema include: test/include/cycle.json -> test/include/cycle.json")
+
+		loader := MapLoader(map[string][]byte{
+			"a.json": []byte(`{"fields": [{"name": "b", "type": "object", "schema": {"$ref": "b.json"}}]}`),
+			"b.json": []byte(`{"fields": [{"name": "items", "type": "array", "items": {"name": "a", "type": "object", "schema": {"$ref": "a.json"}}}]}`),
+		})
+		_, err = ReadFromLoader(loader, "a.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "cyclic schema include: a.json -> b.json -> a.json")
+
+		// names are compared after they are cleaned
+		_, err = ReadFromLoader(DirLoader("test/include"), "./cycle.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "cyclic schema include: cycle.json -> cycle.json")
+
+		loader = MapLoader(map[string][]byte{
+			"/a.json": []byte(`{"fields": [{"name": "a", "type": "object", "schema": {"$ref": "/schemas/../a.json"}}]}`),
+		})
+		_, err = ReadFromLoader(loader, "/a.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "cyclic schema include: /a.json -> /a.json")
+	})
+	t.Run("missing_file", func(t *testing.T) {
+		loader := MapLoader(map[string][]byte{
+			"a.json": []byte(`{"fields": [{"name": "b", "type": "object", "schema": {"$ref": "b.json"}}]}`),
+		})
+		_, err := ReadFromLoader(loader, "a.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "could not read file b.json included by a.json")
+
+		_, err = ReadFromLoader(loader, "c.json")
+		assert.NotNil(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		loader := MapLoader(map[string][]byte{
+			"a.json":      []byte(`{"fields": [{"name": "b", "type": "object", "schema": {"$ref": 1}}]}`),
+			"b.json":      []byte(`{"fields": [{"name": "c", "type": "object", "schema": {"$ref": "c.json"}}]}`),
+			"c.json":      []byte(`[]`),
+			"syntax.json": []byte(`{"fields": [`),
+		})
+		_, err := ReadFromLoader(loader, "a.json")
+		assert.NotNil(t, err)
+		_, err = ReadFromLoader(loader, "b.json")
+		assert.NotNil(t, err)
+		_, err = ReadFromLoader(loader, "syntax.json")
+		assert.NotNil(t, err)
+	})
+	t.Run("keys_with_include", func(t *testing.T) {
+		loader := MapLoader(map[string][]byte{
+			"a.json": []byte(`{"fields": [{"name": "b", "type": "object", "schema": {"$ref": "b.json", "fields": [], "additional_properties": false}}]}`),
+			"b.json": []byte(`{"fields": []}`),
+		})
+		_, err := ReadFromLoader(loader, "a.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "schema /fields/0/schema of file a.json has other keys with $ref key: additional_properties, fields")
+	})
+	t.Run("trailing_data", func(t *testing.T) {
+		loader := MapLoader(map[string][]byte{
+			"a.json": []byte(`{"fields": []} garbage`),
+			"b.json": []byte(`{"fields": [{"name": "c", "type": "object", "schema": {"$ref": "c.json"}}]}`),
+			"c.json": []byte(`{"fields": []}` + "\n" + `{"fields": []}`),
+			"d.json": []byte(`{"fields": [{"name": "e", "type": "object", "schema": {"$ref": "e.json"}}]}` + "\n"),
+			"e.json": []byte(`{"fields": []}` + "\n\t "),
+		})
+		_, err := ReadFromLoader(loader, "a.json")
+		assert.NotNil(t, err)
+		_, err = ReadFromLoader(loader, "b.json")
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "could not parse file c.json included by b.json")
+		_, err = ReadFromLoader(loader, "d.json")
+		assert.Nil(t, err)
+	})
+	t.Run("spec_error_positions", func(t *testing.T) {
+		loader := MapLoader(map[string][]byte{
+			"schemas/user.yaml": []byte(`fields:
+  - name: name
+    type: strin
+  - name: address
+    type: object
+    schema:
+      $ref: ../common/address.yaml
+`),
+			"common/address.yaml": []byte(`definitions:
+  zip:
+    type: integer
+    min: a
+fields:
+  - name: city
+    type: string
+  - name: country
+    type: string
+    min_length: x
+`),
+		})
+		_, err := ReadFromLoader(loader, "schemas/user.yaml")
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "common/address.yaml", errs[0].File)
+		assert.Equal(t, "/definitions/zip", errs[0].Path)
+		assert.Equal(t, 3, errs[0].Line)
+		assert.Equal(t, "", errs[1].File)
+		assert.Equal(t, "/fields/0", errs[1].Path)
+		assert.Equal(t, 2, errs[1].Line)
+		assert.Equal(t, "common/address.yaml", errs[2].File)
+		assert.Equal(t, "/fields/1/schema/fields/1", errs[2].Path)
+		assert.Equal(t, 8, errs[2].Line)
+		assert.Contains(t, errs[2].Error(), "common/address.yaml: line 8, column 5: field /fields/1/schema/fields/1: ")
+
+		// a json file has no positions, but errors of its included YAML files have them
+		loader = MapLoader(map[string][]byte{
+			"user.json":    []byte(`{"fields": [{"name": "address", "type": "object", "schema": {"$ref": "address.yaml"}}]}`),
+			"address.yaml": []byte("fields:\n  - name: zip\n    type: integr\n"),
+		})
+		_, err = ReadFromLoader(loader, "user.json")
+		errs = specErrors(t, err)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "address.yaml", errs[0].File)
+		assert.Equal(t, 2, errs[0].Line)
+	})
+	t.Run("bytes", func(t *testing.T) {
+		_, err := ReadFromString(`{"fields": [{"name": "b", "type": "object", "schema": {"$ref": "b.json"}}]}`)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestReadSpecFromLoader(t *testing.T) {
+	loader := MapLoader(map[string][]byte{
+		"user.yaml": []byte(`
+fields:
+  - name: name
+    type: string
+    min_length: 0
+  - name: address
+    type: object
+    schema:
+      $ref: address.json
+`),
+		"address.json": []byte(`{"fields": [{"name": "zip", "type": "integer", "required": true}]}`),
+		"invalid.json": []byte(`{"fields": [{"name": "zip"}]}`),
+	})
+	spec, err := ReadSpecFromLoader(loader, "user.yaml")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"fields": [
+		{"name": "name", "type": "string", "min_length": 0},
+		{"name": "address", "type": "object", "schema": {"fields": [{"name": "zip", "type": "integer", "required": true}]}}
+	]}`, string(spec))
+
+	_, err = ReadSpecFromLoader(loader, "invalid.json")
+	assert.NotNil(t, err)
+	_, err = ReadSpecFromLoader(loader, "missing.json")
+	assert.NotNil(t, err)
+}
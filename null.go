@@ -1,9 +1,6 @@
 package vjson
 
-import (
-	"encoding/json"
-	"github.com/pkg/errors"
-)
+import "encoding/json"
 
 // NullField is the type for validating floats in a JSON
 type NullField struct {
@@ -23,7 +20,11 @@ func (n *NullField) Validate(input interface{}) error {
 	if input == nil {
 		return nil
 	}
-	return errors.Errorf("Value for %s should be null", n.name)
+	return newValidationError(n.name, typeRule, nullType, input, "Value for %s should be null", n.name)
+}
+
+func (n *NullField) isNullable() bool {
+	return true
 }
 
 func (n *NullField) MarshalJSON() ([]byte, error) {
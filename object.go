@@ -3,13 +3,18 @@ package vjson
 import (
 	"encoding/json"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 )
 
 // ObjectField is the type for validating another JSON object in a JSON
 type ObjectField struct {
 	name     string
 	required bool
+	nullable bool
 	schema   Schema
+
+	validators   customValidators
+	defaultValue interface{}
 }
 
 // To Force Implementing Field interface by ObjectField
@@ -20,30 +25,77 @@ func (o *ObjectField) GetName() string {
 	return o.name
 }
 
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (o *ObjectField) Custom(name string, fn ValidatorFunc) *ObjectField {
+	o.validators = o.validators.add(name, fn)
+	return o
+}
+
+func (o *ObjectField) getValidators() customValidators {
+	return o.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (o *ObjectField) Default(value map[string]interface{}) *ObjectField {
+	o.defaultValue = value
+	return o
+}
+
+func (o *ObjectField) getDefault() (interface{}, bool) {
+	return o.defaultValue, o.defaultValue != nil
+}
+
 // Validate is used for validating a value. it returns an error if the value is invalid.
+// a string is parsed as the json text of an object, so a json object could be validated with the field directly.
 func (o *ObjectField) Validate(v interface{}) error {
+	var err error
+	if text, ok := v.(string); ok {
+		err = o.schema.ValidateString(text)
+	} else {
+		err = o.validateValue(v)
+	}
+	if err != nil || v == nil {
+		return err
+	}
+	return o.validators.validate(o.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+// the value should be a map or a struct, like the value of an object field in a json document.
+func (o *ObjectField) validateValue(v interface{}) error {
 	if v == nil {
 		if !o.required {
 			return nil
 		}
-		return errors.Errorf("Value for %s field is required", o.name)
+		return requiredError(o.name)
 	}
 
-	// The input is either string or an interface{} object
-	value, ok := v.(string)
+	value, err := normalizeValue(v)
+	object, ok := value.(map[string]interface{})
+	if err != nil || !ok {
+		return newValidationError(o.name, typeRule, objectType, v, "Value for %s should be an object", o.name)
+	}
+	return o.schema.validateValue(object)
+}
 
-	var err error
-	var jsonBytes []byte
-	if !ok {
-		jsonBytes, err = json.Marshal(v)
-		if err != nil {
-			return errors.Errorf("Value for %s should be an object", o.name)
-		}
-	} else {
-		return o.schema.ValidateString(value)
+func (o *ObjectField) validateResult(value gjson.Result) error {
+	if !value.Exists() {
+		return o.Validate(nil)
 	}
+	if !value.IsObject() {
+		return newValidationError(o.name, typeRule, objectType, value.Value(), "Value for %s should be an object", o.name)
+	}
+	return o.schema.validateJSON(value)
+}
 
-	return o.schema.ValidateBytes(jsonBytes)
+func (o *ObjectField) compile() error {
+	err := o.schema.compile()
+	if err != nil {
+		return errors.Wrapf(err, "schema of object field %s is invalid", o.name)
+	}
+	return nil
 }
 
 // Required is called to make a field required in a JSON
@@ -52,6 +104,16 @@ func (o *ObjectField) Required() *ObjectField {
 	return o
 }
 
+// Nullable is called to accept null as a valid value of the field in a JSON
+func (o *ObjectField) Nullable() *ObjectField {
+	o.nullable = true
+	return o
+}
+
+func (o *ObjectField) isNullable() bool {
+	return o.nullable
+}
+
 func (o *ObjectField) MarshalJSON() ([]byte, error) {
 	schemaRaw, err := json.Marshal(o.schema)
 	if err != nil {
@@ -59,16 +121,19 @@ func (o *ObjectField) MarshalJSON() ([]byte, error) {
 	}
 
 	schema := make(map[string]interface{})
-	err = json.Unmarshal(schemaRaw, &schema)
+	err = decodeSpec(schemaRaw, &schema)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not unmarshal schema field of array field: %s", o.name)
 	}
 
 	return json.Marshal(ObjectFieldSpec{
-		Name:     o.name,
-		Type:     objectType,
-		Required: o.required,
-		Schema:   schema,
+		Name:       o.name,
+		Type:       objectType,
+		Required:   o.required,
+		Nullable:   o.nullable,
+		Schema:     schema,
+		Validators: o.validators.names(),
+		Default:    o.defaultValue,
 	})
 }
 
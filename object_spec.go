@@ -2,17 +2,23 @@ package vjson
 
 // ObjectFieldSpec is a type used for parsing an ObjectField
 type ObjectFieldSpec struct {
-	Name     string                 `mapstructure:"name" json:"name"`
-	Type     fieldType              `json:"type"`
-	Required bool                   `mapstructure:"required" json:"required,omitempty"`
-	Schema   map[string]interface{} `mapstructure:"schema" json:"schema,omitempty"`
+	Name       string                 `mapstructure:"name" json:"name"`
+	Type       fieldType              `json:"type"`
+	Required   bool                   `mapstructure:"required" json:"required,omitempty"`
+	Nullable   bool                   `mapstructure:"nullable" json:"nullable,omitempty"`
+	Schema     map[string]interface{} `mapstructure:"schema" json:"schema,omitempty"`
+	Validators []string               `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{}            `mapstructure:"default" json:"default,omitempty"`
 }
 
 // NewObject receives an ObjectFieldSpec and returns and ObjectField
 func NewObject(spec ObjectFieldSpec, schema Schema) *ObjectField {
 	return &ObjectField{
-		name:     spec.Name,
-		required: spec.Required,
-		schema:   schema,
+		name:         spec.Name,
+		required:     spec.Required,
+		nullable:     spec.Nullable,
+		schema:       schema,
+		validators:   namedValidators(spec.Validators),
+		defaultValue: spec.Default,
 	}
 }
@@ -0,0 +1,284 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"sort"
+)
+
+const (
+	refType fieldType = "ref"
+	refRule           = "ref"
+)
+
+// RefField is the type for validating a value with a field of the schema definitions.
+// definitions are declared once with Schema.Define or the definitions key of a schema spec, and may refer to
+// themselves, e.g. for trees. a ref field is resolved to its definition when the schema is parsed or defined.
+type RefField struct {
+	name     string
+	required bool
+	nullable bool
+	ref      string
+	field    Field
+
+	validators   customValidators
+	defaultValue interface{}
+}
+
+// To Force Implementing Field interface by RefField
+var _ Field = (*RefField)(nil)
+
+// GetName returns name of the field
+func (r *RefField) GetName() string {
+	return r.name
+}
+
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (r *RefField) Custom(name string, fn ValidatorFunc) *RefField {
+	r.validators = r.validators.add(name, fn)
+	return r
+}
+
+func (r *RefField) getValidators() customValidators {
+	return r.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (r *RefField) Default(value interface{}) *RefField {
+	r.defaultValue = value
+	return r
+}
+
+func (r *RefField) getDefault() (interface{}, bool) {
+	return r.defaultValue, r.defaultValue != nil
+}
+
+// Validate is used for validating a value. it returns an error if the value is invalid.
+func (r *RefField) Validate(v interface{}) error {
+	err := r.validateValue(v)
+	if err != nil || v == nil {
+		return err
+	}
+	return r.validators.validate(r.name, v)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (r *RefField) validateValue(v interface{}) error {
+	if v == nil {
+		if !r.required {
+			return nil
+		}
+		return requiredError(r.name)
+	}
+	field, err := r.definition()
+	if err != nil {
+		return newValidationError(r.name, refRule, r.ref, v, "Value for %s could not be validated: %v", r.name, err)
+	}
+	return validateField(field, v)
+}
+
+func (r *RefField) validateResult(value gjson.Result) error {
+	if !value.Exists() {
+		return r.Validate(nil)
+	}
+	field, err := r.definition()
+	if err != nil {
+		return newValidationError(r.name, refRule, r.ref, value.Value(), "Value for %s could not be validated: %v", r.name, err)
+	}
+	return validateResult(field, value)
+}
+
+// definition returns the field which the ref is resolved to. refs to refs are followed until a field which is not
+// a ref is found, so a definition which only refers to itself is reported instead of looping forever.
+func (r *RefField) definition() (Field, error) {
+	visited := make(map[*RefField]struct{})
+	var field Field = r
+	for {
+		ref, ok := field.(*RefField)
+		if !ok {
+			return field, nil
+		}
+		if _, found := visited[ref]; found {
+			return nil, errors.Errorf("definition %s refers to itself", ref.ref)
+		}
+		visited[ref] = struct{}{}
+		if ref.field == nil {
+			return nil, errors.Errorf("definition %s is not found", ref.ref)
+		}
+		field = ref.field
+	}
+}
+
+func (r *RefField) compile() error {
+	_, err := r.definition()
+	if err != nil {
+		return errors.Wrapf(err, "ref field %s is invalid", r.name)
+	}
+	return nil
+}
+
+// Required is called to make a field required in a JSON
+func (r *RefField) Required() *RefField {
+	r.required = true
+	return r
+}
+
+// Nullable is called to accept null as a valid value of the field in a JSON.
+// a ref field also accepts null if its definition is nullable.
+func (r *RefField) Nullable() *RefField {
+	r.nullable = true
+	return r
+}
+
+func (r *RefField) isNullable() bool {
+	if r.nullable {
+		return true
+	}
+	field, err := r.definition()
+	if err != nil {
+		return false
+	}
+	nullable, ok := field.(nullableField)
+	return ok && nullable.isNullable()
+}
+
+func (r *RefField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RefFieldSpec{
+		Name:       r.name,
+		Type:       refType,
+		Required:   r.required,
+		Nullable:   r.nullable,
+		Ref:        r.ref,
+		Validators: r.validators.names(),
+		Default:    r.defaultValue,
+	})
+}
+
+// Ref is the constructor of a field which is validated with the definition named ref.
+func Ref(name, ref string) *RefField {
+	return &RefField{
+		name: name,
+		ref:  ref,
+	}
+}
+
+// Define adds a definition to the schema and resolves the ref fields of the schema which refer to it.
+// the definition itself may contain ref fields, including refs to itself.
+func (s Schema) Define(name string, field Field) Schema {
+	definitions := make(map[string]Field, len(s.definitions)+1)
+	for definitionName, definition := range s.definitions {
+		definitions[definitionName] = definition
+	}
+	definitions[name] = field
+	s.definitions = definitions
+	s.resolveRefs()
+	return s
+}
+
+// resolveRefs sets the definition of ref fields of the schema, its definitions and its sub-schemas.
+// ref fields which are already resolved, e.g. by a definition of a sub-schema, are not changed.
+func (s *Schema) resolveRefs() {
+	s.forEachRef(func(ref *RefField) {
+		if ref.field == nil {
+			ref.field = s.definitions[ref.ref]
+		}
+	})
+}
+
+// forEachRef calls fn for every ref field of the schema. definitions are walked once and refs are not followed,
+// so recursive definitions do not make it loop.
+func (s *Schema) forEachRef(fn func(ref *RefField)) {
+	for _, field := range s.Fields {
+		forEachRef(field, fn)
+	}
+	forEachRef(s.root, fn)
+	forEachRef(s.additionalField, fn)
+	for _, name := range s.definitionNames() {
+		forEachRef(s.definitions[name], fn)
+	}
+	for _, conditional := range s.conditionals {
+		for _, schema := range conditional.schemas() {
+			schema.forEachRef(fn)
+		}
+	}
+}
+
+func forEachRef(field Field, fn func(ref *RefField)) {
+	switch f := field.(type) {
+	case *RefField:
+		fn(f)
+	case *ObjectField:
+		f.schema.forEachRef(fn)
+	case *ArrayField:
+		forEachRef(f.items, fn)
+	case *CombinatorField:
+		for _, branch := range f.fields {
+			forEachRef(branch, fn)
+		}
+	}
+}
+
+// definitionNames returns the names of schema definitions in sorted order.
+func (s *Schema) definitionNames() []string {
+	names := make([]string, 0, len(s.definitions))
+	for name := range s.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// definitionsParser is shared by a parsed schema and its sub-schemas while parsing, since definitions are only
+// declared in the top-level schema spec but may be used by any nested field.
+type definitionsParser struct {
+	names map[string]struct{}
+	refs  []*RefField
+}
+
+func newDefinitionsParser(definitions map[string]map[string]interface{}) *definitionsParser {
+	names := make(map[string]struct{}, len(definitions))
+	for name := range definitions {
+		names[name] = struct{}{}
+	}
+	return &definitionsParser{names: names}
+}
+
+// parseDefinitions parses the definitions of a top-level schema spec. definitions are named by their keys.
+func (s *Schema) parseDefinitions(specs map[string]map[string]interface{}) error {
+	s.definitions = make(map[string]Field, len(specs))
+	var result error
+	for _, name := range sortedKeys(specs) {
+		field, err := s.getField(namedFieldSpec(specs[name], name))
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not get definition %s", name))
+			continue
+		}
+		s.definitions[name] = field
+	}
+	if len(s.definitions) == 0 {
+		s.definitions = nil
+	}
+	return result
+}
+
+// resolveParsedRefs resolves the ref fields which are found while parsing the schema.
+func (s *Schema) resolveParsedRefs() error {
+	var result error
+	for _, ref := range s.parser.refs {
+		ref.field = s.definitions[ref.ref]
+	}
+	for _, ref := range s.parser.refs {
+		if ref.field == nil {
+			// the definition is invalid and it is already reported
+			continue
+		}
+		if _, err := ref.definition(); err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "ref field %s is invalid", ref.name))
+		}
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+package vjson
+
+// RefFieldSpec is a type used for parsing a RefField
+type RefFieldSpec struct {
+	Name       string      `mapstructure:"name" json:"name"`
+	Type       fieldType   `json:"type"`
+	Required   bool        `mapstructure:"required" json:"required,omitempty"`
+	Nullable   bool        `mapstructure:"nullable" json:"nullable,omitempty"`
+	Ref        string      `mapstructure:"ref" json:"ref"`
+	Validators []string    `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{} `mapstructure:"default" json:"default,omitempty"`
+}
+
+// NewRef receives a RefFieldSpec and returns a RefField
+func NewRef(spec RefFieldSpec) *RefField {
+	return &RefField{
+		name:         spec.Name,
+		required:     spec.Required,
+		nullable:     spec.Nullable,
+		ref:          spec.Ref,
+		validators:   namedValidators(spec.Validators),
+		defaultValue: spec.Default,
+	}
+}
@@ -0,0 +1,209 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const commentsSchema = `{
+	"definitions": {
+		"address": {"type": "object", "schema": {"fields": [{"name": "city", "type": "string", "required": true}]}},
+		"comment": {"type": "object", "schema": {"fields": [
+			{"name": "text", "type": "string", "required": true, "min_length": 1},
+			{"name": "replies", "type": "array", "items": {"name": "reply", "type": "ref", "ref": "comment"}}
+		]}}
+	},
+	"fields": [
+		{"name": "billing", "type": "ref", "ref": "address", "required": true},
+		{"name": "shipping", "type": "ref", "ref": "address", "nullable": true},
+		{"name": "comments", "type": "array", "items": {"name": "comment", "type": "ref", "ref": "comment"}}
+	]
+}`
+
+func TestRefField_GetName(t *testing.T) {
+	field := Ref("billing", "address")
+	assert.Equal(t, "billing", field.GetName())
+}
+
+func TestRefField_Validate(t *testing.T) {
+	schema := NewSchema(
+		Ref("billing", "address").Required(),
+		Ref("shipping", "address").Nullable(),
+		Ref("tags", "tags"),
+	).
+		Define("address", Object("address", NewSchema(String("city").Required()))).
+		Define("tags", Array("tags", String("tag")).Nullable())
+
+	t.Run("valid", func(t *testing.T) {
+		err := schema.ValidateString(`{"billing": {"city": "Paris"}, "shipping": null, "tags": ["a"]}`)
+		assert.Nil(t, err)
+	})
+	t.Run("definition_nullable", func(t *testing.T) {
+		err := schema.ValidateString(`{"billing": {"city": "Paris"}, "tags": null}`)
+		assert.Nil(t, err)
+	})
+	t.Run("required", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/billing", errs[0].Path)
+		assert.Equal(t, "required", errs[0].Rule)
+	})
+	t.Run("not_nullable", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"billing": null}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "nullable", errs[0].Rule)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"billing": {}, "shipping": {"city": 1}, "tags": [1]}`))
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "/billing/city", errs[0].Path)
+		assert.Equal(t, "/shipping/city", errs[1].Path)
+		assert.Equal(t, "/tags/0", errs[2].Path)
+	})
+	t.Run("go_values", func(t *testing.T) {
+		field := schema.definitions["address"]
+		assert.Nil(t, Ref("billing", "address").Validate(nil))
+		assert.NotNil(t, Ref("billing", "address").Required().Validate(nil))
+
+		ref := Ref("billing", "address")
+		ref.field = field
+		assert.Nil(t, ref.Validate(map[string]interface{}{"city": "Paris"}))
+		assert.NotNil(t, ref.Validate(map[string]interface{}{}))
+	})
+	t.Run("unresolved", func(t *testing.T) {
+		schema := NewSchema(Ref("billing", "address"))
+		errs := schema.ValidateDetailed([]byte(`{"billing": {}}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "ref", errs[0].Rule)
+		assert.Equal(t, "Value for billing could not be validated: definition address is not found", errs[0].Message)
+
+		_, err := schema.Compile()
+		assert.NotNil(t, err)
+	})
+	t.Run("self_reference", func(t *testing.T) {
+		schema := NewSchema(Ref("a", "a")).Define("a", Ref("a", "a"))
+		errs := schema.ValidateDetailed([]byte(`{"a": 1}`))
+		assert.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Message, "definition a refers to itself")
+
+		_, err := schema.Compile()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRefField_Recursive(t *testing.T) {
+	schema, err := ReadFromString(commentsSchema)
+	assert.Nil(t, err)
+
+	err = schema.ValidateString(`{
+		"billing": {"city": "Paris"},
+		"comments": [{"text": "a", "replies": [{"text": "b", "replies": [{"text": "c"}]}]}]
+	}`)
+	assert.Nil(t, err)
+
+	errs := schema.ValidateDetailed([]byte(`{
+		"billing": {"city": "Paris"},
+		"comments": [{"text": "a", "replies": [{"text": "b", "replies": [{"text": ""}]}]}]
+	}`))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/comments/0/replies/0/replies/0/text", errs[0].Path)
+
+	// deep documents are validated as deep as they are
+	deep := strings.Repeat(`{"text": "a", "replies": [`, 100) + `{"text": "a"}` + strings.Repeat(`]}`, 100)
+	assert.Nil(t, schema.ValidateString(`{"billing": {"city": "Paris"}, "comments": [`+deep+`]}`))
+
+	_, err = schema.Compile()
+	assert.Nil(t, err)
+}
+
+func TestRefField_MarshalJSON(t *testing.T) {
+	field := Ref("billing", "address").Required().Nullable()
+	content, err := json.Marshal(field)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name": "billing", "type": "ref", "ref": "address", "required": true, "nullable": true}`, string(content))
+
+	schema, err := ReadFromString(commentsSchema)
+	assert.Nil(t, err)
+
+	content, err = json.Marshal(schema)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `"definitions":{"address":{"name":"address","type":"object"`)
+	assert.Contains(t, string(content), `"items":{"name":"reply","ref":"comment","type":"ref"}`)
+
+	parsed, err := ReadFromBytes(content)
+	assert.Nil(t, err)
+	assert.Nil(t, parsed.ValidateString(`{"billing": {"city": "Paris"}, "comments": [{"text": "a", "replies": [{"text": "b"}]}]}`))
+	assert.NotNil(t, parsed.ValidateString(`{"billing": {"city": "Paris"}, "comments": [{"text": "a", "replies": [{}]}]}`))
+}
+
+func TestSchema_Definitions(t *testing.T) {
+	t.Run("root", func(t *testing.T) {
+		schema, err := ReadFromString(`{
+			"definitions": {"node": {"type": "object", "schema": {"fields": [
+				{"name": "value", "type": "integer", "required": true},
+				{"name": "children", "type": "array", "items": {"name": "child", "type": "ref", "ref": "node"}}
+			]}}},
+			"root": {"type": "ref", "ref": "node"}
+		}`)
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`{"value": 1, "children": [{"value": 2, "children": []}]}`))
+		assert.NotNil(t, schema.ValidateString(`{"value": 1, "children": [{}]}`))
+	})
+	t.Run("strict", func(t *testing.T) {
+		schema, err := ReadFromString(`{
+			"definitions": {"address": {"type": "object", "schema": {"fields": [{"name": "city", "type": "string"}]}}},
+			"fields": [{"name": "billing", "type": "ref", "ref": "address"}],
+			"additional_properties": false
+		}`)
+		assert.Nil(t, err)
+		assert.NotNil(t, schema.ValidateString(`{"billing": {"city": "Paris", "zip": 1}}`))
+	})
+	t.Run("definition_name", func(t *testing.T) {
+		schema, err := ReadFromString(`{
+			"definitions": {"code": {"type": "string", "min_length": 2}},
+			"fields": [{"name": "code", "type": "ref", "ref": "code"}]
+		}`)
+		assert.Nil(t, err)
+		assert.Equal(t, "code", schema.definitions["code"].GetName())
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := ReadFromString(`{"fields": [{"name": "billing", "type": "ref", "ref": "address"}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "definition address of ref field billing is not found")
+
+		_, err = ReadFromString(`{"definitions": {"address": {"type": "string"}}, "fields": [{"name": "billing", "type": "ref"}]}`)
+		assert.NotNil(t, err)
+
+		_, err = ReadFromString(`{"definitions": {"address": {"type": "date"}}, "fields": [{"name": "billing", "type": "ref", "ref": "address"}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "could not get definition address")
+
+		_, err = ReadFromString(`{"definitions": {"a": {"type": "ref", "ref": "b"}, "b": {"type": "ref", "ref": "a"}}, "fields": []}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "refers to itself")
+
+		_, err = ReadFromString(`{"fields": [{"name": "inner", "type": "object", "schema": {
+			"definitions": {"a": {"type": "string"}},
+			"fields": []
+		}}]}`)
+		assert.NotNil(t, err)
+	})
+	t.Run("yaml_position", func(t *testing.T) {
+		_, err := ReadFromYAML([]byte(`definitions:
+  address:
+    type: object
+    schema:
+      fields:
+        - name: city
+          type: ref
+          ref: city
+fields: []
+`))
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/definitions/address/schema/fields/0", errs[0].Path)
+		assert.Equal(t, 6, errs[0].Line)
+	})
+}
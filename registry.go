@@ -0,0 +1,69 @@
+package vjson
+
+import (
+	"sync"
+)
+
+// FieldFactory creates a field of a custom type from its spec. parse parses nested field specs, e.g. the items of a
+// custom collection field, with the definitions of the schema which is being parsed.
+type FieldFactory func(spec map[string]interface{}, parse func(map[string]interface{}) (Field, error)) (Field, error)
+
+var fieldTypes = struct {
+	sync.RWMutex
+	factories map[string]FieldFactory
+}{factories: make(map[string]FieldFactory)}
+
+// RegisterFieldType registers a custom field type, so fields with the given type name can be parsed, e.g. money or
+// country_code. the field which is created by factory should marshal itself with the same type name, so a parsed
+// schema can be marshalled and parsed again.
+// registering a type name again replaces its factory. it panics if name is empty or a built-in type, or factory is nil.
+func RegisterFieldType(name string, factory FieldFactory) {
+	if name == "" {
+		panic("vjson: field type name is empty")
+	}
+	if isBuiltinType(fieldType(name)) {
+		panic("vjson: field type " + name + " is a built-in type")
+	}
+	if factory == nil {
+		panic("vjson: factory of field type " + name + " is nil")
+	}
+	fieldTypes.Lock()
+	defer fieldTypes.Unlock()
+	fieldTypes.factories[name] = factory
+}
+
+// lookupFieldType returns the factory of a custom field type.
+func lookupFieldType(name string) (FieldFactory, bool) {
+	fieldTypes.RLock()
+	defer fieldTypes.RUnlock()
+	factory, found := fieldTypes.factories[name]
+	return factory, found
+}
+
+var validatorFuncs = struct {
+	sync.RWMutex
+	funcs map[string]ValidatorFunc
+}{funcs: make(map[string]ValidatorFunc)}
+
+// RegisterValidator registers a validator function by name, so parsed schemas can use it in validators key of their
+// fields, e.g. "validators": ["luhn"]. registering a name again replaces its function.
+// it panics if name is empty or fn is nil.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	if name == "" {
+		panic("vjson: validator name is empty")
+	}
+	if fn == nil {
+		panic("vjson: function of validator " + name + " is nil")
+	}
+	validatorFuncs.Lock()
+	defer validatorFuncs.Unlock()
+	validatorFuncs.funcs[name] = fn
+}
+
+// lookupValidator returns the function of a registered validator.
+func lookupValidator(name string) (ValidatorFunc, bool) {
+	validatorFuncs.RLock()
+	defer validatorFuncs.RUnlock()
+	fn, found := validatorFuncs.funcs[name]
+	return fn, found
+}
@@ -0,0 +1,165 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+var countryCodeRegex = regexp.MustCompile("^[A-Z]{2}$")
+
+// countryCodeField is a custom field type of the tests, for ISO 3166 country codes.
+type countryCodeField struct {
+	name     string
+	required bool
+}
+
+func (c *countryCodeField) GetName() string {
+	return c.name
+}
+
+func (c *countryCodeField) Validate(v interface{}) error {
+	if v == nil {
+		if c.required {
+			return requiredError(c.name)
+		}
+		return nil
+	}
+	code, ok := v.(string)
+	if !ok || !countryCodeRegex.MatchString(code) {
+		return errors.Errorf("Value for %s should be a country code", c.name)
+	}
+	return nil
+}
+
+func (c *countryCodeField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"name": c.name, "type": "country_code", "required": c.required})
+}
+
+// listField is a custom field type of the tests, which validates arrays with a nested field.
+type listField struct {
+	name string
+	item Field
+}
+
+func (l *listField) GetName() string {
+	return l.name
+}
+
+func (l *listField) Validate(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		return errors.Errorf("Value for %s should be a list", l.name)
+	}
+	for index, item := range items {
+		err := l.item.Validate(item)
+		if err != nil {
+			return prefixIndex(err, index)
+		}
+	}
+	return nil
+}
+
+func (l *listField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"name": l.name, "type": "list", "item": l.item})
+}
+
+func init() {
+	RegisterFieldType("country_code", func(spec map[string]interface{}, _ func(map[string]interface{}) (Field, error)) (Field, error) {
+		var codeSpec struct {
+			Name     string `mapstructure:"name"`
+			Required bool   `mapstructure:"required"`
+		}
+		err := mapstructure.Decode(spec, &codeSpec)
+		if err != nil {
+			return nil, err
+		}
+		return &countryCodeField{name: codeSpec.Name, required: codeSpec.Required}, nil
+	})
+	RegisterFieldType("list", func(spec map[string]interface{}, parse func(map[string]interface{}) (Field, error)) (Field, error) {
+		name, _ := spec["name"].(string)
+		itemSpec, ok := spec["item"].(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("item key is missing for list field name: %s", name)
+		}
+		item, err := parse(itemSpec)
+		if err != nil {
+			return nil, err
+		}
+		return &listField{name: name, item: item}, nil
+	})
+}
+
+func TestRegisterFieldType(t *testing.T) {
+	schemaStr := `{
+		"definitions": {"code": {"type": "country_code"}},
+		"fields": [
+			{"name": "country", "type": "country_code", "required": true},
+			{"name": "visited", "type": "list", "item": {"name": "visited", "type": "ref", "ref": "code"}},
+			{"name": "tags", "type": "array", "items": {"name": "tag", "type": "country_code"}}
+		]
+	}`
+
+	t.Run("parse", func(t *testing.T) {
+		schema, err := ReadFromString(schemaStr)
+		assert.Nil(t, err)
+
+		assert.Nil(t, schema.ValidateString(`{"country": "FR", "visited": ["DE", "IT"], "tags": ["NL"]}`))
+		errs := schema.ValidateDetailed([]byte(`{"country": "fr", "visited": ["DE", "x"], "tags": [1]}`))
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "/country", errs[0].Path)
+		assert.Equal(t, "invalid", errs[0].Rule)
+		assert.Equal(t, "/visited/1", errs[1].Path)
+		assert.Equal(t, "/tags/0", errs[2].Path)
+
+		errs = schema.ValidateDetailed([]byte(`{}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "required", errs[0].Rule)
+	})
+	t.Run("code", func(t *testing.T) {
+		schema := NewSchema(&countryCodeField{name: "country", required: true})
+		assert.Nil(t, schema.ValidateString(`{"country": "FR"}`))
+		assert.NotNil(t, schema.ValidateString(`{"country": "France"}`))
+	})
+	t.Run("marshal", func(t *testing.T) {
+		schema, err := ReadFromString(schemaStr)
+		assert.Nil(t, err)
+
+		content, err := json.Marshal(schema)
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), `{"name":"country","required":true,"type":"country_code"}`)
+
+		parsed, err := ReadFromBytes(content)
+		assert.Nil(t, err)
+		assert.Equal(t, schema.Fields[0], parsed.Fields[0])
+		assert.NotNil(t, parsed.ValidateString(`{"country": "FR", "visited": ["x"]}`))
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := ReadFromString(`{"fields": [{"name": "visited", "type": "list"}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "could not get list field: item key is missing for list field name: visited")
+
+		_, err = ReadFromString(`{"fields": [{"name": "visited", "type": "list", "item": {"name": "a", "type": "date"}}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "Invalid type: date")
+	})
+	t.Run("invalid_registration", func(t *testing.T) {
+		factory := func(map[string]interface{}, func(map[string]interface{}) (Field, error)) (Field, error) {
+			return nil, nil
+		}
+		assert.Panics(t, func() { RegisterFieldType("", factory) })
+		assert.Panics(t, func() { RegisterFieldType("string", factory) })
+		assert.Panics(t, func() { RegisterFieldType("money", nil) })
+
+		RegisterFieldType("nothing", factory)
+		_, err := ReadFromString(`{"fields": [{"name": "a", "type": "nothing"}]}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "factory of nothing type returned a nil field")
+	})
+}
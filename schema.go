@@ -1,34 +1,162 @@
 package vjson
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/hashicorp/go-multierror"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
-	"io/ioutil"
-	"os"
+	"path/filepath"
+	"reflect"
 )
 
 // Schema is the type for declaring a JSON schema and validating a json object.
+// a schema which is created by NewRootSchema validates the whole document with its root field instead of Fields,
+// e.g. a top-level array or string.
 type Schema struct {
 	Fields []Field `json:"fields"`
+
+	root                 Field
+	additionalProperties additionalPropertiesMode
+	additionalField      Field
+	definitions          map[string]Field
+	parser               *definitionsParser
+	conditionals         []*Conditional
+	dependencies         []dependency
+	comparisons          []*Comparison
+	compiled             *compiledSchema
 }
 
+// rootFieldName is the name of the root field of a parsed schema if its spec has no name.
+const rootFieldName = "root"
+
 // SchemaSpec is used for parsing a Schema
 type SchemaSpec struct {
 	Fields []map[string]interface{} `json:"fields"`
+	// Root is the specification of a field which validates the whole document. it can not be used with Fields.
+	Root map[string]interface{} `json:"root,omitempty"`
+	// AdditionalProperties is either a boolean which allows or denies undeclared properties,
+	// or a field specification which undeclared properties are validated with.
+	AdditionalProperties interface{} `json:"additional_properties,omitempty"`
+	// Definitions are field specifications which are used by ref fields, by their names.
+	// they can only be declared in the top-level schema.
+	Definitions map[string]map[string]interface{} `json:"definitions,omitempty"`
+	// Conditions are conditional rules which validate the object with then or else schema specs,
+	// depending on whether it is valid according to the if schema spec.
+	Conditions []ConditionalSpec `json:"conditions,omitempty"`
+	// DependentRequired makes fields required if another field is present, e.g. {"start_date": ["end_date"]}.
+	DependentRequired map[string][]string `json:"dependent_required,omitempty"`
+	// Comparisons compare two fields of the object, e.g. {"left": "start", "operator": "less_than", "right": "end"}.
+	Comparisons []ComparisonSpec `json:"comparisons,omitempty"`
+	// Include is the path of a file which contains the schema. it is only resolved by ReadFromFile and ReadFromLoader.
+	Include string `json:"$ref,omitempty"`
+}
+
+type schemaJSON struct {
+	Fields               []Field             `json:"fields"`
+	AdditionalProperties interface{}         `json:"additional_properties,omitempty"`
+	Definitions          map[string]Field    `json:"definitions,omitempty"`
+	Conditions           []*Conditional      `json:"conditions,omitempty"`
+	DependentRequired    map[string][]string `json:"dependent_required,omitempty"`
+	Comparisons          []*Comparison       `json:"comparisons,omitempty"`
+}
+
+type rootSchemaJSON struct {
+	Root              Field               `json:"root"`
+	Definitions       map[string]Field    `json:"definitions,omitempty"`
+	Conditions        []*Conditional      `json:"conditions,omitempty"`
+	DependentRequired map[string][]string `json:"dependent_required,omitempty"`
+	Comparisons       []*Comparison       `json:"comparisons,omitempty"`
 }
 
 // UnmarshalJSON is implemented for parsing a Schema. it overrides json.Unmarshal behaviour.
-func (s *Schema) UnmarshalJSON(bytes []byte) error {
+func (s *Schema) UnmarshalJSON(input []byte) error {
 	var schemaSpec SchemaSpec
-	err := json.Unmarshal(bytes, &schemaSpec)
+	err := decodeSpec(input, &schemaSpec)
 	if err != nil {
 		return errors.Wrap(err, "could not unmarshal to SchemaSpec")
 	}
+
+	if s.parser != nil {
+		// a sub-schema of a schema which is being parsed uses the definitions of the top-level schema
+		if len(schemaSpec.Definitions) > 0 {
+			return errors.Errorf("definitions key can only be used in the top-level schema")
+		}
+		return s.parseSpec(schemaSpec)
+	}
+
+	s.parser = newDefinitionsParser(schemaSpec.Definitions)
+	defer func() {
+		s.parser = nil
+	}()
+
+	var result error
+	err = s.parseDefinitions(schemaSpec.Definitions)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	err = s.parseSpec(schemaSpec)
+	if err != nil {
+		if result == nil {
+			return err
+		}
+		result = multierror.Append(result, err)
+	}
+	err = s.resolveParsedRefs()
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	return result
+}
+
+// decodeSpec decodes a json schema spec. numbers are decoded as json.Number, so bounds and defaults of decimal fields
+// keep their exact text.
+func decodeSpec(input []byte, spec interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	decoder.UseNumber()
+	err := decoder.Decode(spec)
+	if err != nil {
+		return err
+	}
+	if rest := bytes.Trim(input[decoder.InputOffset():], " \t\r\n"); len(rest) > 0 {
+		return errors.Errorf("invalid character %q after top-level value", rest[0])
+	}
+	return nil
+}
+
+// parseSpec sets the fields of the schema from a schema spec.
+func (s *Schema) parseSpec(schemaSpec SchemaSpec) error {
 	s.Fields = make([]Field, 0, len(schemaSpec.Fields))
 
+	if schemaSpec.Include != "" {
+		return errors.Errorf("schema includes %s, includes are only resolved by ReadFromFile and ReadFromLoader", schemaSpec.Include)
+	}
+	if schemaSpec.Root != nil {
+		if len(schemaSpec.Fields) > 0 {
+			return errors.Errorf("root and fields keys can not be used together")
+		}
+		if schemaSpec.AdditionalProperties != nil {
+			// additional properties of the document are validated by the root field, e.g. by the schema of an object root
+			return errors.Errorf("root and additional_properties keys can not be used together")
+		}
+		root, err := s.getField(namedFieldSpec(schemaSpec.Root, rootFieldName))
+		if err != nil {
+			return errors.Wrap(err, "could not get root field")
+		}
+		s.root = root
+		var result error
+		err = s.setRulesSpec(schemaSpec.Conditions, schemaSpec.DependentRequired)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		err = s.setComparisonsSpec(schemaSpec.Comparisons)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		return result
+	}
+
 	var result error
 
 	for _, fieldSpec := range schemaSpec.Fields {
@@ -40,9 +168,63 @@ func (s *Schema) UnmarshalJSON(bytes []byte) error {
 		s.Fields = append(s.Fields, field)
 	}
 
+	err := s.setAdditionalPropertiesSpec(schemaSpec.AdditionalProperties)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	s.propagateAdditionalProperties()
+
+	err = s.setRulesSpec(schemaSpec.Conditions, schemaSpec.DependentRequired)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	err = s.setComparisonsSpec(schemaSpec.Comparisons)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+
 	return result
 }
 
+// namedFieldSpec returns the spec of a field with a default name, for fields which are not looked up by their names
+// like root fields and definitions.
+func namedFieldSpec(spec map[string]interface{}, name string) map[string]interface{} {
+	if specName, _ := spec["name"].(string); specName != "" {
+		return spec
+	}
+	named := make(map[string]interface{}, len(spec)+1)
+	for key, value := range spec {
+		named[key] = value
+	}
+	named["name"] = name
+	return named
+}
+
+// MarshalJSON is implemented for serializing a Schema in the same format which is parsed by UnmarshalJSON.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.root != nil {
+		return json.Marshal(rootSchemaJSON{
+			Root:              s.root,
+			Definitions:       s.definitions,
+			Conditions:        s.conditionals,
+			DependentRequired: s.dependenciesSpec(),
+			Comparisons:       s.comparisons,
+		})
+	}
+	fields := s.Fields
+	if fields == nil {
+		fields = []Field{}
+	}
+	return json.Marshal(schemaJSON{
+		Fields:               fields,
+		AdditionalProperties: s.additionalPropertiesSpec(),
+		Definitions:          s.definitions,
+		Conditions:           s.conditionals,
+		DependentRequired:    s.dependenciesSpec(),
+		Comparisons:          s.comparisons,
+	})
+}
+
 func (s *Schema) getField(fieldSpec map[string]interface{}) (Field, error) {
 	fieldTypeRaw, found := fieldSpec[typeKey]
 	if found {
@@ -106,9 +288,44 @@ func (s *Schema) getField(fieldSpec map[string]interface{}) (Field, error) {
 					}
 					return field, nil
 				}
+			case decimalType:
+				{
+					field, err := s.getDecimalField(fieldSpec)
+					if err != nil {
+						return nil, err
+					}
+					return field, nil
+				}
+			case oneOfType, anyOfType, allOfType, notType:
+				{
+					field, err := s.getCombinatorField(fieldType, fieldSpec)
+					if err != nil {
+						return nil, err
+					}
+					return field, nil
+				}
+			case refType:
+				{
+					field, err := s.getRefField(fieldSpec)
+					if err != nil {
+						return nil, err
+					}
+					return field, nil
+				}
 			default:
 				{
-					return nil, errors.Errorf("Invalid type: %s", fieldType)
+					factory, found := lookupFieldType(fieldTypeStr)
+					if !found {
+						return nil, errors.Errorf("Invalid type: %s", fieldType)
+					}
+					field, err := factory(fieldSpec, s.getField)
+					if err != nil {
+						return nil, errors.Wrapf(err, "could not get %s field", fieldType)
+					}
+					if field == nil {
+						return nil, errors.Errorf("factory of %s type returned a nil field", fieldType)
+					}
+					return field, nil
 				}
 			}
 		}
@@ -206,6 +423,25 @@ func (s *Schema) getArrayField(fieldSpec map[string]interface{}) (*ArrayField, e
 	return arrayField, nil
 }
 
+func (s *Schema) getDecimalField(fieldSpec map[string]interface{}) (*DecimalField, error) {
+	var decimalSpec DecimalFieldSpec
+	err := decodeDecimalSpec(fieldSpec, &decimalSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode decimal field to DecimalFieldSpec")
+	}
+	if decimalSpec.Name == "" {
+		return nil, errors.Errorf("name field is required for a decimal field")
+	}
+
+	_, minValidation := fieldSpec["min"]
+	_, maxValidation := fieldSpec["max"]
+	_, rangeValidation := fieldSpec["ranges"]
+
+	decimalField := NewDecimal(decimalSpec, minValidation, maxValidation, rangeValidation)
+
+	return decimalField, nil
+}
+
 func (s *Schema) getBooleanField(fieldSpec map[string]interface{}) (*BooleanField, error) {
 	var booleanSpec BooleanFieldSpec
 	err := mapstructure.Decode(fieldSpec, &booleanSpec)
@@ -242,19 +478,29 @@ func (s *Schema) getObjectField(fieldSpec map[string]interface{}) (*ObjectField,
 		return nil, errors.Errorf("invalid format for schema key for object field name: %s", objectSpec.Name)
 	}
 
+	schema, err := s.getSchema(schemaSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal schema spec to schema for object field name: %s", objectSpec.Name)
+	}
+
+	objectField := NewObject(objectSpec, schema)
+	return objectField, nil
+}
+
+// getSchema parses a sub-schema spec, e.g. the schema of an object field. the sub-schema uses the definitions of s.
+func (s *Schema) getSchema(schemaSpec map[string]interface{}) (Schema, error) {
 	jsonSchemaSpec, err := json.Marshal(schemaSpec)
 	if err != nil {
-		return nil, errors.Errorf("could not marshal schema to json for object field name: %s", objectSpec.Name)
+		return Schema{}, errors.Wrap(err, "could not marshal schema spec to json")
 	}
 
-	var schema Schema
+	schema := Schema{parser: s.parser}
 	err = json.Unmarshal(jsonSchemaSpec, &schema)
+	schema.parser = nil
 	if err != nil {
-		return nil, errors.Errorf("could not unmarshal schema spec to schema for object field name: %s", objectSpec.Name)
+		return Schema{}, err
 	}
-
-	objectField := NewObject(objectSpec, schema)
-	return objectField, nil
+	return schema, nil
 }
 
 func (s *Schema) getNullField(fieldSpec map[string]interface{}) (*NullField, error) {
@@ -272,6 +518,64 @@ func (s *Schema) getNullField(fieldSpec map[string]interface{}) (*NullField, err
 	return nullField, nil
 }
 
+func (s *Schema) getCombinatorField(fieldType fieldType, fieldSpec map[string]interface{}) (*CombinatorField, error) {
+	var combinatorSpec CombinatorFieldSpec
+	err := mapstructure.Decode(fieldSpec, &combinatorSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decode %s field to CombinatorFieldSpec", fieldType)
+	}
+	if combinatorSpec.Name == "" {
+		return nil, errors.Errorf("name field is required for a %s field", fieldType)
+	}
+	combinatorSpec.Type = fieldType
+
+	fieldSpecs := combinatorSpec.Fields
+	if fieldType == notType {
+		if combinatorSpec.Field == nil {
+			return nil, errors.Errorf("field key is missing for not field name: %s", combinatorSpec.Name)
+		}
+		fieldSpecs = []map[string]interface{}{combinatorSpec.Field}
+	} else if len(fieldSpecs) == 0 {
+		return nil, errors.Errorf("fields key is missing for %s field name: %s", fieldType, combinatorSpec.Name)
+	}
+
+	fields := make([]Field, 0, len(fieldSpecs))
+	for index, spec := range fieldSpecs {
+		field, err := s.getField(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get field %d of %s field name: %s", index, fieldType, combinatorSpec.Name)
+		}
+		fields = append(fields, field)
+	}
+
+	combinatorField := NewCombinator(combinatorSpec, fields)
+	return combinatorField, nil
+}
+
+func (s *Schema) getRefField(fieldSpec map[string]interface{}) (*RefField, error) {
+	var refSpec RefFieldSpec
+	err := mapstructure.Decode(fieldSpec, &refSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode ref field to RefFieldSpec")
+	}
+	if refSpec.Name == "" {
+		return nil, errors.Errorf("name field is required for a ref field")
+	}
+	if refSpec.Ref == "" {
+		return nil, errors.Errorf("ref key is missing for ref field name: %s", refSpec.Name)
+	}
+	if s.parser == nil {
+		return nil, errors.Errorf("definition %s of ref field %s is not found", refSpec.Ref, refSpec.Name)
+	}
+	if _, found := s.parser.names[refSpec.Ref]; !found {
+		return nil, errors.Errorf("definition %s of ref field %s is not found", refSpec.Ref, refSpec.Name)
+	}
+
+	refField := NewRef(refSpec)
+	s.parser.refs = append(s.parser.refs, refField)
+	return refField, nil
+}
+
 // ValidateBytes receives a byte array of a json object and validates it according to the specified Schema.
 // it returns an error if the input is invalid.
 func (s *Schema) ValidateBytes(input []byte) error {
@@ -279,7 +583,7 @@ func (s *Schema) ValidateBytes(input []byte) error {
 		jsonObject := gjson.ParseBytes(input)
 		return s.validateJSON(jsonObject)
 	}
-	return errors.Errorf("could not parse json input.")
+	return invalidJSONError()
 }
 
 // ValidateString is like ValidateBytes but it receives the json object as string input.
@@ -288,27 +592,143 @@ func (s *Schema) ValidateString(input string) error {
 		jsonObject := gjson.Parse(input)
 		return s.validateJSON(jsonObject)
 	}
-	return errors.Errorf("could not parse json input.")
+	return invalidJSONError()
 }
 
 func (s *Schema) validateJSON(json gjson.Result) error {
+	if s.root != nil {
+		var result error
+		err := validateResult(s.root, json)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		err = s.validateRules(json)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		if result == nil {
+			err = s.validateComparisons(json, nil)
+			if err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		return result
+	}
+
 	var result error
-	for _, field := range s.Fields {
+	var invalid map[string]bool
+	values := s.lookupFields(json)
+	for index, field := range s.Fields {
 		fieldName := field.GetName()
-		fieldValue := json.Get(fieldName).Value()
-		err := field.Validate(fieldValue)
+		err := validateResult(field, values[index])
 		if err != nil {
-			result = multierror.Append(result, errors.Wrapf(err, "Field %s is invalid.", fieldName))
+			result = multierror.Append(result, prefixPath(err, fieldName))
+			if invalid == nil {
+				invalid = make(map[string]bool)
+			}
+			invalid[fieldName] = true
 		}
 	}
+
+	err := s.validateAdditionalProperties(json)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	err = s.validateRules(json)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	err = s.validateComparisons(json, invalid)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
 	return result
 }
 
+// lookupFields finds the values of schema fields in a json object with a single pass over the object.
+// the value of a field which is not present in the object does not exist.
+func (s *Schema) lookupFields(json gjson.Result) []gjson.Result {
+	values := make([]gjson.Result, len(s.Fields))
+	if !json.IsObject() {
+		return values
+	}
+	json.ForEach(func(key, value gjson.Result) bool {
+		name := key.String()
+		if s.compiled != nil {
+			for _, index := range s.compiled.indexes[name] {
+				if !values[index].Exists() {
+					values[index] = value
+				}
+			}
+			return true
+		}
+		for index, field := range s.Fields {
+			if !values[index].Exists() && field.GetName() == name {
+				values[index] = value
+			}
+		}
+		return true
+	})
+	return values
+}
+
+// Unmarshal fills the missing fields of a json object with their default values like Apply, validates the result
+// according to the Schema and then stores it in the value pointed to by dst, like json.Unmarshal.
+// the input is decoded once into a new value, which also checks its syntax, and it is walked once for defaults and
+// validation. it is decoded again only if a default value is inserted. dst is set to the decoded value if the input
+// is valid, so it is not modified if the input is invalid, and the validation error is returned.
+func (s *Schema) Unmarshal(input []byte, dst interface{}) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return errors.Errorf("could not unmarshal json input into %T, it should be a non-nil pointer", dst)
+	}
+	decoded := reflect.New(target.Type().Elem())
+	decodeErr := json.Unmarshal(input, decoded.Interface())
+	var syntaxErr *json.SyntaxError
+	if errors.As(decodeErr, &syntaxErr) {
+		return invalidJSONError()
+	}
+
+	value := gjson.ParseBytes(input)
+	output, err := s.applyDefaults(value, make(map[Field]bool))
+	if err != nil {
+		return err
+	}
+	if string(output) != value.Raw {
+		value = gjson.ParseBytes(output)
+		decoded = reflect.New(target.Type().Elem())
+		decodeErr = json.Unmarshal(output, decoded.Interface())
+	}
+	err = s.validateJSON(value)
+	if err != nil {
+		return err
+	}
+	// a valid input may not fit in dst, e.g. a string for an int field
+	if decodeErr != nil {
+		return errors.Wrap(decodeErr, "could not unmarshal json input")
+	}
+	target.Elem().Set(decoded.Elem())
+	return nil
+}
+
+// ValidateDetailed is like ValidateBytes but it returns a flat list of ValidationError.
+// the list is empty if the input is valid.
+func (s *Schema) ValidateDetailed(input []byte) []ValidationError {
+	return toValidationErrors(s.ValidateBytes(input))
+}
+
 // NewSchema is the constructor for Schema. it receives a list of Field in its arguments.
 func NewSchema(fields ...Field) Schema {
 	return Schema{Fields: fields}
 }
 
+// NewRootSchema is the constructor of a Schema which validates the whole document with a field, instead of looking up
+// fields by name in a json object. e.g. NewRootSchema(Array("items", Integer("item"))) validates a top-level array.
+// errors of the root field have an empty path, and the name of the field is only used in messages.
+func NewRootSchema(field Field) Schema {
+	return Schema{root: field}
+}
+
 // ReadFromString is for parsing a Schema from a string input.
 func ReadFromString(input string) (*Schema, error) {
 	return ReadFromBytes([]byte(input))
@@ -325,14 +745,9 @@ func ReadFromBytes(input []byte) (*Schema, error) {
 }
 
 // ReadFromFile is for parsing a Schema from a file input.
+// the format of the file is detected by its extension: .yaml and .yml files are parsed with ReadFromYAML,
+// .toml files with ReadFromTOML and other files are parsed as json.
+// object fields may include schemas of other files with $ref, which are resolved relative to the file, see ReadFromLoader.
 func ReadFromFile(filePath string) (*Schema, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not open file given, path: %s", filePath)
-	}
-	input, err := ioutil.ReadAll(file)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not read file given, path: %s", filePath)
-	}
-	return ReadFromBytes(input)
+	return ReadFromLoader(DirLoader(""), filepath.ToSlash(filePath))
 }
@@ -247,6 +247,45 @@ func TestReadFromFile(t *testing.T) {
 			assert.Nil(t, schema)
 		})
 	})
+	t.Run("combinator", func(t *testing.T) {
+		t.Run("valid", func(t *testing.T) {
+			schema, err := ReadFromFile("test/combinator.json")
+			assert.Nil(t, err)
+			assert.Len(t, schema.Fields, 2)
+			assert.Equal(t, oneOfType, schema.Fields[0].(*CombinatorField).kind)
+			assert.Equal(t, true, schema.Fields[0].(*CombinatorField).required)
+			assert.Len(t, schema.Fields[0].(*CombinatorField).fields, 2)
+			assert.Equal(t, notType, schema.Fields[1].(*CombinatorField).kind)
+			assert.Len(t, schema.Fields[1].(*CombinatorField).fields, 1)
+
+			assert.Nil(t, schema.ValidateString(`{"id": "abc", "code": "user"}`))
+			assert.Nil(t, schema.ValidateString(`{"id": 12}`))
+			assert.NotNil(t, schema.ValidateString(`{"id": -12}`))
+			assert.NotNil(t, schema.ValidateString(`{"id": "abc", "code": "admin"}`))
+		})
+
+		t.Run("invalid", func(t *testing.T) {
+			_, err := ReadFromString(`{"fields":[{"name":"id","type":"one_of"}]}`)
+			assert.NotNil(t, err)
+
+			_, err = ReadFromString(`{"fields":[{"name":"id","type":"not"}]}`)
+			assert.NotNil(t, err)
+
+			_, err = ReadFromString(`{"fields":[{"name":"id","type":"any_of","fields":[{"type":"string"}]}]}`)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("nullable", func(t *testing.T) {
+		schema, err := ReadFromFile("test/nullable.json")
+		assert.Nil(t, err)
+		assert.Len(t, schema.Fields, 2)
+		assert.Equal(t, true, schema.Fields[0].(*StringField).nullable)
+		assert.Equal(t, false, schema.Fields[1].(*IntegerField).nullable)
+
+		assert.Nil(t, schema.ValidateString(`{"nickname": null}`))
+		assert.NotNil(t, schema.ValidateString(`{"nickname": null, "age": null}`))
+		assert.NotNil(t, schema.ValidateString(`{}`))
+	})
 	t.Run("invalid_type", func(t *testing.T) {
 		schema, err := ReadFromFile("test/invalid_type.json")
 		assert.NotNil(t, err)
@@ -273,6 +312,12 @@ func TestReadFromString(t *testing.T) {
 	schema, err := ReadFromString(`{"fields":[{"name":"bar","type": "string","required":true}]}`)
 	assert.Nil(t, err)
 	assert.Len(t, schema.Fields, 1)
+
+	// errors of nested schemas keep their cause
+	_, err = ReadFromString(`{"fields":[{"name":"bar","type":"object","schema":{"fields":[{"name":"baz","type":"date"}]}}]}`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "could not unmarshal schema spec to schema for object field name: bar")
+	assert.Contains(t, err.Error(), "Invalid type: date")
 }
 
 func TestNewSchema(t *testing.T) {
@@ -430,6 +475,148 @@ func TestSchema_ValidateMap(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestSchema_Nullable(t *testing.T) {
+	schema := NewSchema(
+		Integer("integer").Nullable(),
+		Float("float").Nullable(),
+		String("string").Required().Nullable(),
+		Boolean("boolean").Nullable(),
+		Array("array", Integer("item").Nullable()).Nullable(),
+		Object("object", NewSchema()).Nullable(),
+		Null("null"),
+	)
+
+	err := schema.ValidateString(`{"integer": null, "float": null, "string": null, "boolean": null, "array": [1, null], "object": null, "null": null}`)
+	assert.Nil(t, err)
+
+	// required means the key must be present, even if it is nullable
+	errs := schema.ValidateDetailed([]byte(`{}`))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/string", errs[0].Path)
+	assert.Equal(t, "required", errs[0].Rule)
+
+	notNullable := NewSchema(
+		Integer("integer"),
+		String("string").Required(),
+		Array("array", Integer("item")),
+		Object("object", NewSchema(Boolean("boolean"))),
+	)
+
+	// missing optional fields are valid
+	err = notNullable.ValidateString(`{"string": "foo"}`)
+	assert.Nil(t, err)
+
+	errs = notNullable.ValidateDetailed([]byte(`{"integer": null, "string": null, "array": [1, null], "object": {"boolean": null}}`))
+	assert.Len(t, errs, 4)
+	assert.Equal(t, "/integer", errs[0].Path)
+	assert.Equal(t, "nullable", errs[0].Rule)
+	assert.Equal(t, "/string", errs[1].Path)
+	assert.Equal(t, "/array/1", errs[2].Path)
+	assert.Equal(t, "/object/boolean", errs[3].Path)
+
+	oneOf := NewSchema(OneOf("id", String("id"), Null("id")))
+	assert.Nil(t, oneOf.ValidateString(`{"id": null}`))
+	notNullableOneOf := NewSchema(OneOf("id", String("id"), Integer("id")))
+	assert.NotNil(t, notNullableOneOf.ValidateString(`{"id": null}`))
+}
+
+func TestSchema_Unmarshal(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type person struct {
+		Name    string   `json:"name"`
+		Age     int      `json:"age"`
+		Tags    []string `json:"tags"`
+		Address address  `json:"address"`
+	}
+	schema := NewSchema(
+		String("name").Required(),
+		Integer("age").Min(18),
+		Array("tags", String("tag")),
+		Object("address", NewSchema(String("city").Required())),
+	)
+
+	var p person
+	err := schema.Unmarshal([]byte(`{"name": "John", "age": 30, "tags": ["a"], "address": {"city": "Paris"}}`), &p)
+	assert.Nil(t, err)
+	assert.Equal(t, person{Name: "John", Age: 30, Tags: []string{"a"}, Address: address{City: "Paris"}}, p)
+
+	var invalid person
+	err = schema.Unmarshal([]byte(`{"name": "John", "age": 10, "address": {}}`), &invalid)
+	assert.NotNil(t, err)
+	assert.Equal(t, person{}, invalid)
+	errs := toValidationErrors(err)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "/age", errs[0].Path)
+	assert.Equal(t, "/address/city", errs[1].Path)
+
+	err = schema.Unmarshal([]byte(`{{`), &invalid)
+	assert.NotNil(t, err)
+
+	// the input is valid but it does not fit in dst
+	var wrongType struct {
+		Name int `json:"name"`
+	}
+	wrongType.Name = 5
+	err = schema.Unmarshal([]byte(`{"name": "John"}`), &wrongType)
+	assert.NotNil(t, err)
+	assert.Equal(t, 5, wrongType.Name)
+
+	// validation errors are reported before errors of decoding into dst
+	errs = toValidationErrors(schema.Unmarshal([]byte(`{"name": 1}`), &wrongType))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/name", errs[0].Path)
+
+	errs = toValidationErrors(schema.Unmarshal([]byte(`{"name": "John"} x`), &p))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, invalidJSONError().Rule, errs[0].Rule)
+
+	assert.NotNil(t, schema.Unmarshal([]byte(`{"name": "John"}`), p))
+	assert.NotNil(t, schema.Unmarshal([]byte(`{"name": "John"}`), (*person)(nil)))
+
+	// dst is set to the decoded value, so its fields which are not in the input are reset
+	replaced := person{Name: "Jane", Age: 40}
+	err = schema.Unmarshal([]byte(`{"name": "John"}`), &replaced)
+	assert.Nil(t, err)
+	assert.Equal(t, person{Name: "John"}, replaced)
+
+	validator, err := schema.Compile()
+	assert.Nil(t, err)
+	var compiled person
+	err = validator.Unmarshal([]byte(`{"name": "Jane"}`), &compiled)
+	assert.Nil(t, err)
+	assert.Equal(t, "Jane", compiled.Name)
+
+	t.Run("defaults", func(t *testing.T) {
+		schema := NewSchema(
+			String("name").Required(),
+			Integer("age").Min(18).Default(18),
+			Array("tags", String("tag")).Default([]interface{}{"new"}),
+			Object("address", NewSchema(String("city").Default("Paris"))),
+		)
+		input := []byte(`{"name": "John", "address": {}}`)
+
+		var p person
+		err := schema.Unmarshal(input, &p)
+		assert.Nil(t, err)
+		assert.Equal(t, person{Name: "John", Age: 18, Tags: []string{"new"}, Address: address{City: "Paris"}}, p)
+
+		output, err := schema.Apply(input)
+		assert.Nil(t, err)
+		var applied person
+		assert.Nil(t, json.Unmarshal(output, &applied))
+		assert.Equal(t, applied, p)
+
+		// defaults are validated too
+		schema = NewSchema(String("name").Required(), Integer("age").Default(10).Min(18))
+		var invalid person
+		err = schema.Unmarshal([]byte(`{"name": "John"}`), &invalid)
+		assert.NotNil(t, err)
+		assert.Equal(t, person{}, invalid)
+	})
+}
+
 func TestSchema_EmptySchema(t *testing.T) {
 	emptySchema := Schema{
 		Fields: []Field{},
@@ -487,3 +674,81 @@ func BenchmarkSchema_ValidateBytes(b *testing.B) {
 		_ = s.ValidateBytes(jsonBytes)
 	}
 }
+
+func TestSchema_Root(t *testing.T) {
+	t.Run("array", func(t *testing.T) {
+		schema := NewRootSchema(Array("users", Object("user", NewSchema(
+			Integer("id").Required(),
+		))).MaxLength(2)).Strict()
+
+		assert.Nil(t, schema.ValidateString(`[{"id": 1}, {"id": 2}]`))
+		assert.Nil(t, schema.ValidateString(`[]`))
+
+		errs := schema.ValidateDetailed([]byte(`[{"id": 1}, {"name": "foo"}]`))
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "/1/id", errs[0].Path)
+		assert.Equal(t, "required", errs[0].Rule)
+		assert.Equal(t, "/1/name", errs[1].Path)
+		assert.Equal(t, "additional_properties", errs[1].Rule)
+
+		errs = schema.ValidateDetailed([]byte(`{"id": 1}`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "", errs[0].Path)
+		assert.Equal(t, "type", errs[0].Rule)
+
+		var users []struct {
+			ID int `json:"id"`
+		}
+		err := schema.Unmarshal([]byte(`[{"id": 1}, {"id": 2}]`), &users)
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+	})
+	t.Run("scalar", func(t *testing.T) {
+		schema := NewRootSchema(String("code").Choices("a", "b"))
+		assert.Nil(t, schema.ValidateString(`"a"`))
+		assert.NotNil(t, schema.ValidateString(`"c"`))
+		assert.NotNil(t, schema.ValidateString(`1`))
+		assert.NotNil(t, schema.ValidateString(`null`))
+
+		schema = NewRootSchema(String("code").Nullable())
+		assert.Nil(t, schema.ValidateString(`null`))
+	})
+	t.Run("compile", func(t *testing.T) {
+		_, err := NewRootSchema(String("code").Format("(")).Compile()
+		assert.NotNil(t, err)
+	})
+	t.Run("parse", func(t *testing.T) {
+		schema, err := ReadFromFile("test/root.json")
+		assert.Nil(t, err)
+		assert.Len(t, schema.Fields, 0)
+		assert.Equal(t, "root", schema.root.GetName())
+		assert.Nil(t, schema.ValidateString(`[{"id": 1}]`))
+		assert.NotNil(t, schema.ValidateString(`[{"id": 1}, {"id": 2}, {"id": 3}]`))
+		assert.NotNil(t, schema.ValidateString(`{"id": 1}`))
+
+		_, err = ReadFromString(`{"root": {"type": "string"}, "fields": [{"name": "foo", "type": "string"}]}`)
+		assert.NotNil(t, err)
+
+		_, err = ReadFromString(`{"root": {"type": "date"}}`)
+		assert.NotNil(t, err)
+
+		_, err = ReadFromString(`{"root": {"type": "object", "schema": {"fields": []}}, "additional_properties": false}`)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "root and additional_properties keys can not be used together")
+
+		_, err = ReadFromYAML([]byte("root:\n  type: string\nadditional_properties: false\n"))
+		assert.NotNil(t, err)
+	})
+	t.Run("marshal", func(t *testing.T) {
+		schema := NewRootSchema(Array("", Integer("item").Min(1)))
+		raw, err := json.Marshal(schema)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"root": {"name": "", "type": "array", "items": {"name": "item", "type": "integer", "min": 1}}}`, string(raw))
+
+		var parsed Schema
+		err = json.Unmarshal(raw, &parsed)
+		assert.Nil(t, err)
+		assert.Nil(t, parsed.ValidateString(`[1, 2]`))
+		assert.NotNil(t, parsed.ValidateString(`[0]`))
+	})
+}
@@ -0,0 +1,268 @@
+package vjson
+
+import (
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+const tomlRule = "toml"
+
+// ReadFromTOML is for parsing a Schema from a TOML input. the TOML document has the same format as a json schema spec,
+// e.g. fields are defined with [[fields]] tables. errors of invalid field definitions are SpecError values which
+// point at their line and column.
+func ReadFromTOML(input []byte) (*Schema, error) {
+	document, err := tomlDocument(input)
+	if err != nil {
+		if position, ok := tomlErrorPosition(input, err); ok {
+			return nil, &SpecError{Line: position.line, Column: position.column, Err: err}
+		}
+		return nil, errors.Wrap(err, "could not parse toml schema")
+	}
+	return readFromDocument(document, tomlPositions(input))
+}
+
+// ValidateTOML receives a TOML document and validates it according to the Schema like the equivalent json object.
+// Line and Column of the returned validation errors are the position of the invalid values in the TOML document.
+// TOML decoder does not keep positions of values, so the position of a value in an inline table or array is the
+// position of the key which contains it.
+func (s *Schema) ValidateTOML(input []byte) error {
+	document, err := tomlDocument(input)
+	if err != nil {
+		validationError := newValidationError("", tomlRule, nil, nil, "could not parse toml input: %v", err)
+		if position, ok := tomlErrorPosition(input, err); ok {
+			validationError.Line = position.line
+			validationError.Column = position.column
+		}
+		return validationError
+	}
+	return s.validateDocument(document, tomlPositions(input))
+}
+
+// tomlDocument decodes a TOML input to the values of a json document.
+func tomlDocument(input []byte) (map[string]interface{}, error) {
+	document := make(map[string]interface{})
+	_, err := toml.Decode(string(input), &document)
+	if err != nil {
+		var parseError toml.ParseError
+		if errors.As(err, &parseError) {
+			return nil, parseError
+		}
+		return nil, err
+	}
+	return document, nil
+}
+
+// tomlErrorPosition returns the position of a TOML parse error.
+func tomlErrorPosition(input []byte, err error) (documentPosition, bool) {
+	parseError, ok := err.(toml.ParseError)
+	if !ok {
+		return documentPosition{}, false
+	}
+	line, column := offsetPosition(input, parseError.Position.Start)
+	if parseError.Position.Line > 0 {
+		line = parseError.Position.Line
+	}
+	return documentPosition{line: line, column: column}, true
+}
+
+// tomlPositions finds positions of tables and keys in a TOML document. TOML decoder does not keep positions of keys,
+// so lines are scanned for headers of tables like [[fields]] or [fields.items] and for keys like name = "a".
+// lines of multi-line strings and arrays and comments are skipped. values in inline tables and arrays have no positions,
+// so they are located at the key which contains them.
+func tomlPositions(input []byte) documentPositions {
+	positions := documentPositions{"": {line: 1, column: 1}}
+	// lastIndex keeps the index of the last table of each array of tables, e.g. /fields/0/schema/fields
+	lastIndex := make(map[string]int)
+	table := ""
+	var scanner tomlScanner
+
+	for index, line := range strings.Split(string(input), "\n") {
+		if scanner.inValue() {
+			// the line continues a multi-line string or array of the previous key
+			scanner.scan(line)
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		position := documentPosition{line: index + 1, column: len(line) - len(strings.TrimLeft(line, " \t")) + 1}
+
+		if strings.HasPrefix(trimmed, "[") {
+			parts, arrayOfTables, ok := tomlHeader(trimmed)
+			if !ok {
+				continue
+			}
+			if !arrayOfTables {
+				table = tomlTablePointer(parts, lastIndex, false)
+				positions[table] = position
+				continue
+			}
+			pointer := tomlTablePointer(parts, lastIndex, true)
+			next := 0
+			if last, found := lastIndex[pointer]; found {
+				next = last + 1
+			}
+			lastIndex[pointer] = next
+			table = pointer + "/" + strconv.Itoa(next)
+			positions[table] = position
+			continue
+		}
+
+		separator := tomlIndex(trimmed, "=")
+		if comment := tomlIndex(trimmed, "#"); separator <= 0 || (comment >= 0 && comment < separator) {
+			continue
+		}
+		// dotted keys like schema.name = "a" define their tables too
+		pointer := table
+		for _, part := range splitTOMLKey(trimmed[:separator]) {
+			pointer += "/" + escapePointerToken(part)
+			if _, found := positions[pointer]; !found {
+				positions[pointer] = position
+			}
+		}
+		positions[pointer] = position
+		scanner.scan(trimmed[separator+1:])
+	}
+	return positions
+}
+
+// tomlScanner follows values of a TOML document which continue on the next lines, i.e. multi-line strings and arrays.
+type tomlScanner struct {
+	// multiline is the delimiter of the multi-line string which is not closed yet, `"""` or `'''`.
+	multiline string
+	// depth is the number of arrays which are not closed yet.
+	depth int
+}
+
+func (s *tomlScanner) inValue() bool {
+	return s.multiline != "" || s.depth > 0
+}
+
+// scan reads a line of a value, or the rest of the line of a key after =. strings and comments are skipped.
+func (s *tomlScanner) scan(text string) {
+	for index := 0; index < len(text); index++ {
+		if s.multiline != "" {
+			if s.multiline == `"""` && text[index] == '\\' {
+				index++
+			} else if strings.HasPrefix(text[index:], s.multiline) {
+				index += len(s.multiline) - 1
+				s.multiline = ""
+			}
+			continue
+		}
+		switch c := text[index]; {
+		case strings.HasPrefix(text[index:], `"""`) || strings.HasPrefix(text[index:], `'''`):
+			s.multiline = text[index : index+3]
+			index += 2
+		case c == '"' || c == '\'':
+			// other strings end on the same line
+			for index++; index < len(text) && text[index] != c; index++ {
+				if c == '"' && text[index] == '\\' {
+					index++
+				}
+			}
+		case c == '#':
+			return
+		case c == '[':
+			s.depth++
+		case c == ']' && s.depth > 0:
+			s.depth--
+		}
+	}
+}
+
+// tomlHeader parses the header of a table like [fields.items], or of an array of tables like [[fields]], and returns
+// the parts of its key.
+func tomlHeader(line string) ([]string, bool, bool) {
+	arrayOfTables := strings.HasPrefix(line, "[[")
+	start, closing := 1, "]"
+	if arrayOfTables {
+		start, closing = 2, "]]"
+	}
+	end := tomlIndex(line[start:], closing)
+	if end < 0 {
+		return nil, false, false
+	}
+	return splitTOMLKey(line[start : start+end]), arrayOfTables, true
+}
+
+// tomlIndex returns the index of the first separator in a TOML text which is not in a quoted key or string, or -1.
+func tomlIndex(text, separator string) int {
+	quote := byte(0)
+	for index := 0; index < len(text); index++ {
+		c := text[index]
+		switch {
+		case quote != 0:
+			if quote == '"' && c == '\\' {
+				index++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(text[index:], separator):
+			return index
+		}
+	}
+	return -1
+}
+
+// splitTOMLKey splits a dotted TOML key to its parts, e.g. fields."a.b" to fields and a.b.
+func splitTOMLKey(key string) []string {
+	var parts []string
+	for {
+		end := tomlIndex(key, ".")
+		part := key
+		if end >= 0 {
+			part = key[:end]
+		}
+		parts = append(parts, unquoteTOMLKey(strings.TrimSpace(part)))
+		if end < 0 {
+			return parts
+		}
+		key = key[end+1:]
+	}
+}
+
+func unquoteTOMLKey(part string) string {
+	if len(part) < 2 || (part[0] != '"' && part[0] != '\'') || part[len(part)-1] != part[0] {
+		return part
+	}
+	if part[0] == '"' {
+		if unquoted, err := strconv.Unquote(part); err == nil {
+			return unquoted
+		}
+	}
+	return part[1 : len(part)-1]
+}
+
+// tomlTablePointer converts the parts of a TOML table key to a pointer. arrays of tables in the key refer to their last
+// table, except the last part of the key of an array of tables, which is the array itself.
+func tomlTablePointer(parts []string, lastIndex map[string]int, arrayOfTables bool) string {
+	pointer := ""
+	for index, part := range parts {
+		pointer += "/" + escapePointerToken(part)
+		if arrayOfTables && index == len(parts)-1 {
+			break
+		}
+		if last, found := lastIndex[pointer]; found {
+			pointer += "/" + strconv.Itoa(last)
+		}
+	}
+	return pointer
+}
+
+// offsetPosition returns the line and column of a byte offset of an input.
+func offsetPosition(input []byte, offset int) (int, int) {
+	if offset > len(input) {
+		offset = len(input)
+	}
+	line := 1 + strings.Count(string(input[:offset]), "\n")
+	column := offset - strings.LastIndex(string(input[:offset]), "\n")
+	return line, column
+}
+
+// isTOMLFile reports whether a file is a TOML file by its extension.
+func isTOMLFile(filePath string) bool {
+	return strings.HasSuffix(strings.ToLower(filePath), ".toml")
+}
@@ -0,0 +1,165 @@
+package vjson
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadFromTOML(t *testing.T) {
+	t.Run("same_as_json", func(t *testing.T) {
+		jsonSchema, err := ReadFromFile("test/person.json")
+		assert.Nil(t, err)
+
+		tomlSchema, err := ReadFromFile("test/person.toml")
+		assert.Nil(t, err)
+		assert.Equal(t, jsonSchema, tomlSchema)
+	})
+	t.Run("syntax_error", func(t *testing.T) {
+		_, err := ReadFromTOML([]byte("[[fields]]\nname = \"foo\" \"bar\"\ntype = \"string\"\n"))
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, 2, errs[0].Line)
+		assert.Equal(t, 13, errs[0].Column)
+	})
+	t.Run("invalid_field", func(t *testing.T) {
+		_, err := ReadFromTOML([]byte(`[[fields]]
+name = "foo"
+type = "string"
+
+[[fields]]
+name = "bar"
+type = "date"
+`))
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/fields/1", errs[0].Path)
+		assert.Equal(t, 5, errs[0].Line)
+		assert.Equal(t, 1, errs[0].Column)
+	})
+	t.Run("invalid_nested_field", func(t *testing.T) {
+		_, err := ReadFromTOML([]byte(`[[fields]]
+name = "address"
+type = "object"
+
+  [[fields.schema.fields]]
+  name = "city"
+  type = "string"
+
+  [[fields.schema.fields]]
+  name = "zip"
+  type = "zip"
+
+[[fields]]
+name = "list"
+type = "array"
+items = { name = "item", type = "unknown" }
+`))
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "/fields/0/schema/fields/1", errs[0].Path)
+		assert.Equal(t, 9, errs[0].Line)
+		assert.Equal(t, 3, errs[0].Column)
+		assert.Equal(t, "/fields/1/items", errs[1].Path)
+		assert.Equal(t, 16, errs[1].Line)
+	})
+}
+
+func TestTOMLPositions(t *testing.T) {
+	positions := tomlPositions([]byte(`[[fields]]
+name = "a"
+[[fields]]
+name = "b"
+[fields.schema]
+[[fields.schema.fields]]
+[[fields.schema.fields]]
+[[fields]]
+`))
+	assert.Equal(t, documentPosition{line: 1, column: 1}, positions["/fields/0"])
+	assert.Equal(t, documentPosition{line: 2, column: 1}, positions["/fields/0/name"])
+	assert.Equal(t, documentPosition{line: 3, column: 1}, positions["/fields/1"])
+	assert.Equal(t, documentPosition{line: 5, column: 1}, positions["/fields/1/schema"])
+	assert.Equal(t, documentPosition{line: 7, column: 1}, positions["/fields/1/schema/fields/1"])
+	assert.Equal(t, documentPosition{line: 8, column: 1}, positions["/fields/2"])
+	assert.Equal(t, documentPosition{line: 8, column: 1}, positions.find("/fields/2/items"))
+
+	t.Run("strings_and_comments", func(t *testing.T) {
+		positions := tomlPositions([]byte(`# type = "comment" [[fields]]
+[[fields]] # name = "comment"
+name = "a" # format = "comment"
+description = """
+format = "in a string"
+[[fields]]
+"""
+literal = '''
+type = 'in a string' '''
+ranges = [
+  { start = 1, end = 2 },
+  "[[fields]]", # min = 1
+]
+type = "string"
+"a.b" = 1
+schema.format = "x=y"
+["a.b".c]
+`))
+		assert.Equal(t, documentPosition{line: 2, column: 1}, positions["/fields/0"])
+		assert.Equal(t, documentPosition{line: 3, column: 1}, positions["/fields/0/name"])
+		assert.Equal(t, documentPosition{line: 4, column: 1}, positions["/fields/0/description"])
+		assert.Equal(t, documentPosition{line: 8, column: 1}, positions["/fields/0/literal"])
+		assert.Equal(t, documentPosition{line: 10, column: 1}, positions["/fields/0/ranges"])
+		assert.Equal(t, documentPosition{line: 14, column: 1}, positions["/fields/0/type"])
+		assert.Equal(t, documentPosition{line: 15, column: 1}, positions["/fields/0/a.b"])
+		assert.Equal(t, documentPosition{line: 16, column: 1}, positions["/fields/0/schema"])
+		assert.Equal(t, documentPosition{line: 16, column: 1}, positions["/fields/0/schema/format"])
+		assert.Equal(t, documentPosition{line: 17, column: 1}, positions["/a.b/c"])
+		for _, pointer := range []string{"/fields/1", "/fields/0/format", "/fields/0/min", "/fields/0/start", "/type"} {
+			_, found := positions[pointer]
+			assert.False(t, found, pointer)
+		}
+	})
+}
+
+func TestSchema_ValidateTOML(t *testing.T) {
+	schema, err := ReadFromFile("test/person.toml")
+	assert.Nil(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		err := schema.ValidateTOML([]byte(`
+name = "John"
+tags = ["a", "b"]
+
+[address]
+city = "Paris"
+`))
+		assert.Nil(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		err := schema.ValidateTOML([]byte(`name = "john"
+tags = ["a", "c"]
+
+[address]
+zip = -1
+`))
+		errs := toValidationErrors(err)
+		assert.Len(t, errs, 4)
+
+		assert.Equal(t, "/name", errs[0].Path)
+		assert.Equal(t, 1, errs[0].Line)
+
+		// values of inline arrays are reported at the position of their key
+		assert.Equal(t, "/tags/1", errs[1].Path)
+		assert.Equal(t, 2, errs[1].Line)
+
+		assert.Equal(t, "/address/city", errs[2].Path)
+		assert.Equal(t, 4, errs[2].Line)
+
+		assert.Equal(t, "/address/zip", errs[3].Path)
+		assert.Equal(t, 5, errs[3].Line)
+		assert.Equal(t, 1, errs[3].Column)
+	})
+	t.Run("invalid_toml", func(t *testing.T) {
+		errs := toValidationErrors(schema.ValidateTOML([]byte("name = \"john\" \"doe\"\n")))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "toml", errs[0].Rule)
+		assert.Equal(t, 1, errs[0].Line)
+	})
+}
@@ -0,0 +1,237 @@
+package vjson
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+const (
+	yamlRule      = "yaml"
+	yamlMergeTag  = "!!merge"
+	yamlTimeTag   = "!!timestamp"
+	yamlBinaryTag = "!!binary"
+)
+
+// ReadFromYAML is for parsing a Schema from a YAML input. the YAML document has the same format as a json schema spec.
+// errors of invalid field definitions are SpecError values which point at their line and column.
+func ReadFromYAML(input []byte) (*Schema, error) {
+	document, positions, err := yamlDocument(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse yaml schema")
+	}
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	return readFromDocument(document, positions)
+}
+
+// ValidateYAML receives a YAML document and validates it according to the Schema like the equivalent json object.
+// Line and Column of the returned validation errors are the position of the invalid values in the YAML document.
+func (s *Schema) ValidateYAML(input []byte) error {
+	document, positions, err := yamlDocument(input)
+	if err != nil {
+		return newValidationError("", yamlRule, nil, nil, "could not parse yaml input: %v", err)
+	}
+	return s.validateDocument(document, positions)
+}
+
+// yamlMaxAliasedNodes is the maximum number of nodes which could be read through aliases of a YAML document, so a
+// small document with nested aliases could not be expanded to a huge one.
+const yamlMaxAliasedNodes = 100000
+
+// yamlDocument decodes a YAML input to the values of a json document and finds their positions.
+func yamlDocument(input []byte) (interface{}, documentPositions, error) {
+	var root yaml.Node
+	err := yaml.Unmarshal(input, &root)
+	if err != nil {
+		return nil, nil, err
+	}
+	document, err := newYAMLAliases().value(&root)
+	if err != nil {
+		return nil, nil, err
+	}
+	positions := make(documentPositions)
+	err = newYAMLAliases().positions(&root, "", positions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, positions, nil
+}
+
+// yamlAliases keeps the aliases which are being expanded while a YAML document is read, and the number of nodes which
+// are read through aliases.
+type yamlAliases struct {
+	expanding map[*yaml.Node]bool
+	depth     int
+	nodes     int
+}
+
+func newYAMLAliases() *yamlAliases {
+	return &yamlAliases{expanding: make(map[*yaml.Node]bool)}
+}
+
+// visit counts a node which is read through an alias.
+func (a *yamlAliases) visit(node *yaml.Node) error {
+	if a.depth == 0 {
+		return nil
+	}
+	a.nodes++
+	if a.nodes > yamlMaxAliasedNodes {
+		return errors.Errorf("line %d, column %d: aliases are expanded to more than %d nodes", node.Line, node.Column, yamlMaxAliasedNodes)
+	}
+	return nil
+}
+
+// expand calls read for the node of an alias. an alias to a node which contains the alias itself is an error.
+func (a *yamlAliases) expand(node *yaml.Node, read func(*yaml.Node) error) error {
+	if a.expanding[node.Alias] {
+		return errors.Errorf("line %d, column %d: alias %s refers to itself", node.Line, node.Column, node.Value)
+	}
+	a.expanding[node.Alias] = true
+	a.depth++
+	err := read(node.Alias)
+	a.depth--
+	delete(a.expanding, node.Alias)
+	return err
+}
+
+// value converts a YAML node to a value which could be encoded as json. keys of mappings are converted to strings,
+// and timestamps are kept as they are written, since json has no time type.
+func (a *yamlAliases) value(node *yaml.Node) (interface{}, error) {
+	err := a.visit(node)
+	if err != nil {
+		return nil, err
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return a.value(node.Content[0])
+	case yaml.AliasNode:
+		var value interface{}
+		err = a.expand(node, func(alias *yaml.Node) error {
+			value, err = a.value(alias)
+			return err
+		})
+		return value, err
+	case yaml.SequenceNode:
+		values := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := a.value(child)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case yaml.MappingNode:
+		return a.mapping(node)
+	}
+
+	switch node.ShortTag() {
+	case yamlTimeTag, yamlBinaryTag:
+		return node.Value, nil
+	}
+	var value interface{}
+	err = node.Decode(&value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "line %d, column %d", node.Line, node.Column)
+	}
+	return value, nil
+}
+
+// mapping converts a YAML mapping node to a map. keys which are merged with << are overridden by other keys.
+func (a *yamlAliases) mapping(node *yaml.Node) (map[string]interface{}, error) {
+	mapping := make(map[string]interface{}, len(node.Content)/2)
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		key, value := node.Content[index], node.Content[index+1]
+		if key.ShortTag() != yamlMergeTag {
+			continue
+		}
+		merged := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			merged = value.Content
+		}
+		for _, mergedNode := range merged {
+			mergedValue, err := a.value(mergedNode)
+			if err != nil {
+				return nil, err
+			}
+			mergedMapping, ok := mergedValue.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("line %d, column %d: merged value should be a mapping", mergedNode.Line, mergedNode.Column)
+			}
+			for mergedKey, mergedValue := range mergedMapping {
+				if _, found := mapping[mergedKey]; !found {
+					mapping[mergedKey] = mergedValue
+				}
+			}
+		}
+	}
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		key, value := node.Content[index], node.Content[index+1]
+		if key.ShortTag() == yamlMergeTag {
+			continue
+		}
+		if key.Kind != yaml.ScalarNode {
+			return nil, errors.Errorf("line %d, column %d: key should be a scalar", key.Line, key.Column)
+		}
+		converted, err := a.value(value)
+		if err != nil {
+			return nil, err
+		}
+		mapping[key.Value] = converted
+	}
+	return mapping, nil
+}
+
+// positions adds positions of a YAML node and its children to positions.
+func (a *yamlAliases) positions(node *yaml.Node, pointer string, positions documentPositions) error {
+	err := a.visit(node)
+	if err != nil {
+		return err
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			err = a.positions(child, pointer, positions)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case yaml.AliasNode:
+		return a.expand(node, func(alias *yaml.Node) error {
+			return a.positions(alias, pointer, positions)
+		})
+	}
+
+	positions[pointer] = documentPosition{line: node.Line, column: node.Column}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			key := node.Content[index].Value
+			err = a.positions(node.Content[index+1], pointer+"/"+escapePointerToken(key), positions)
+			if err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for index, child := range node.Content {
+			err = a.positions(child, pointer+"/"+strconv.Itoa(index), positions)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isYAMLFile reports whether a file is a YAML file by its extension.
+func isYAMLFile(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	return strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml")
+}
@@ -0,0 +1,183 @@
+package vjson
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// specErrors returns SpecError values of an error returned by ReadFromYAML or ReadFromTOML.
+func specErrors(t *testing.T, err error) []*SpecError {
+	var result []*SpecError
+	if specError, ok := err.(*SpecError); ok {
+		return append(result, specError)
+	}
+	multiError, ok := errors.Cause(err).(*multierror.Error)
+	if !assert.True(t, ok, "error should be a multierror: %v", err) {
+		return nil
+	}
+	for _, e := range multiError.Errors {
+		specError, ok := e.(*SpecError)
+		assert.True(t, ok, "error should be a SpecError: %v", e)
+		result = append(result, specError)
+	}
+	return result
+}
+
+func TestReadFromYAML(t *testing.T) {
+	t.Run("same_as_json", func(t *testing.T) {
+		jsonSchema, err := ReadFromFile("test/person.json")
+		assert.Nil(t, err)
+
+		yamlSchema, err := ReadFromFile("test/person.yaml")
+		assert.Nil(t, err)
+		assert.Equal(t, jsonSchema, yamlSchema)
+
+		err = yamlSchema.ValidateString(`{"name": "John", "tags": ["a"], "address": {"city": "Paris", "zip": 75}}`)
+		assert.Nil(t, err)
+		err = yamlSchema.ValidateString(`{"name": "John", "address": {"city": "Paris", "country": "France"}}`)
+		assert.NotNil(t, err)
+	})
+	t.Run("empty", func(t *testing.T) {
+		schema, err := ReadFromYAML([]byte(""))
+		assert.Nil(t, err)
+		assert.Len(t, schema.Fields, 0)
+	})
+	t.Run("syntax_error", func(t *testing.T) {
+		_, err := ReadFromYAML([]byte("fields:\n  - name: [foo\n"))
+		assert.NotNil(t, err)
+	})
+	t.Run("alias_cycle", func(t *testing.T) {
+		_, err := ReadFromYAML([]byte("a: &a [*a]\n"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "refers to itself")
+	})
+	t.Run("alias_expansion", func(t *testing.T) {
+		document := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+		for level := 'b'; level <= 'g'; level++ {
+			document += fmt.Sprintf("%c: &%c [*%c, *%c, *%c, *%c, *%c, *%c, *%c, *%c, *%c, *%c]\n",
+				level, level, level-1, level-1, level-1, level-1, level-1, level-1, level-1, level-1, level-1, level-1)
+		}
+		_, err := ReadFromYAML([]byte(document))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "aliases are expanded to more than")
+	})
+	t.Run("invalid_field", func(t *testing.T) {
+		_, err := ReadFromYAML([]byte(`fields:
+  - name: foo
+    type: string
+  - name: bar
+    type: date
+`))
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, 4, errs[0].Line)
+		assert.Equal(t, 5, errs[0].Column)
+		assert.Equal(t, "/fields/1", errs[0].Path)
+		assert.Equal(t, "line 4, column 5: field /fields/1: Invalid type: date", errs[0].Error())
+	})
+	t.Run("invalid_nested_field", func(t *testing.T) {
+		_, err := ReadFromYAML([]byte(`fields:
+  - name: list
+    type: array
+    items:
+      name: person
+      type: object
+      schema:
+        fields:
+          - name: age
+            type: integer
+          - type: string
+  - name: other
+    type: array
+`))
+		errs := specErrors(t, err)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "/fields/0/items/schema/fields/1", errs[0].Path)
+		assert.Equal(t, 11, errs[0].Line)
+		assert.Equal(t, 13, errs[0].Column)
+		assert.Equal(t, "/fields/1", errs[1].Path)
+		assert.Equal(t, 12, errs[1].Line)
+	})
+}
+
+func TestSchema_ValidateYAML(t *testing.T) {
+	schema, err := ReadFromFile("test/person.yaml")
+	assert.Nil(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		err := schema.ValidateYAML([]byte(`
+name: John
+age: 30
+tags: [a, b]
+address:
+  city: Paris
+`))
+		assert.Nil(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		err := schema.ValidateYAML([]byte(`
+name: john
+tags:
+  - a
+  - c
+address:
+  zip: -1
+`))
+		errs := toValidationErrors(err)
+		assert.Len(t, errs, 4)
+
+		assert.Equal(t, "/name", errs[0].Path)
+		assert.Equal(t, 2, errs[0].Line)
+		assert.Equal(t, 7, errs[0].Column)
+
+		assert.Equal(t, "/tags/1", errs[1].Path)
+		assert.Equal(t, 5, errs[1].Line)
+		assert.Equal(t, 5, errs[1].Column)
+
+		// missing values are reported at the position of their parent
+		assert.Equal(t, "/address/city", errs[2].Path)
+		assert.Equal(t, "required", errs[2].Rule)
+		assert.Equal(t, 7, errs[2].Line)
+		assert.Equal(t, 3, errs[2].Column)
+
+		assert.Equal(t, "/address/zip", errs[3].Path)
+		assert.Equal(t, "line 7, column 8: /address/zip: Value for zip should be a positive float", errs[3].Error())
+	})
+	t.Run("timestamp", func(t *testing.T) {
+		schema := NewSchema(String("date").Format(`^\d{4}-\d{2}-\d{2}$`))
+		err := schema.ValidateYAML([]byte("date: 2021-01-02\n"))
+		assert.Nil(t, err)
+	})
+	t.Run("anchors", func(t *testing.T) {
+		schema := NewSchema(
+			Object("base", NewSchema(Integer("port").Required())),
+			Object("override", NewSchema(Integer("port").Max(100), String("host").Required())),
+		)
+		err := schema.ValidateYAML([]byte(`
+base: &base
+  port: 80
+  host: localhost
+override:
+  <<: *base
+  port: 8080
+`))
+		errs := toValidationErrors(err)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/override/port", errs[0].Path)
+		assert.Equal(t, 7, errs[0].Line)
+	})
+	t.Run("cyclic_alias", func(t *testing.T) {
+		errs := toValidationErrors(schema.ValidateYAML([]byte("name: &a [*a]\n")))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "yaml", errs[0].Rule)
+		assert.Contains(t, errs[0].Error(), "refers to itself")
+	})
+	t.Run("invalid_yaml", func(t *testing.T) {
+		errs := toValidationErrors(schema.ValidateYAML([]byte("name: [john\n")))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "yaml", errs[0].Rule)
+	})
+}
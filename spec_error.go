@@ -0,0 +1,152 @@
+package vjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+)
+
+// SpecError is an error of a schema specification in a YAML or TOML document.
+// it points at the line and column of the invalid field definition.
+type SpecError struct {
+	// File is the name of the included file which contains the invalid definition, if the schema is read by
+	// ReadFromLoader or ReadFromFile. it is empty if the definition is in the file which is read.
+	File string
+	// Line and Column are the position of the invalid definition in the document, starting at 1.
+	// they are zero if the definition is in a json file.
+	Line   int
+	Column int
+	// Path is the JSON Pointer of the invalid definition in the schema spec, e.g. /fields/0/items.
+	Path string
+	Err  error
+}
+
+func (e *SpecError) Error() string {
+	location := ""
+	if e.File != "" {
+		location = e.File + ": "
+	}
+	if e.Line > 0 {
+		location += fmt.Sprintf("line %d, column %d: ", e.Line, e.Column)
+	}
+	if e.Path == "" {
+		return fmt.Sprintf("%s%v", location, e.Err)
+	}
+	return fmt.Sprintf("%sfield %s: %v", location, e.Path, e.Err)
+}
+
+// Cause returns the underlying error, so errors.Cause works with a SpecError.
+func (e *SpecError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the underlying error.
+func (e *SpecError) Unwrap() error {
+	return e.Err
+}
+
+// locateSpecErrors finds invalid field definitions of a schema spec and returns a SpecError for each of them.
+// err is returned if no invalid definition is found.
+func locateSpecErrors(input []byte, positions documentPositions, err error) error {
+	var schemaSpec SchemaSpec
+	if json.Unmarshal(input, &schemaSpec) != nil {
+		return err
+	}
+
+	// ref fields are checked against the names of the definitions of the spec
+	s := Schema{parser: newDefinitionsParser(schemaSpec.Definitions)}
+	var result error
+	for _, name := range sortedKeys(schemaSpec.Definitions) {
+		result = appendSpecError(&s, result, positions, namedFieldSpec(schemaSpec.Definitions[name], name), "/definitions/"+escapePointerToken(name))
+	}
+	if schemaSpec.Root != nil {
+		result = appendSpecError(&s, result, positions, namedFieldSpec(schemaSpec.Root, rootFieldName), "/root")
+	}
+	for index, fieldSpec := range schemaSpec.Fields {
+		result = appendSpecError(&s, result, positions, fieldSpec, "/fields/"+strconv.Itoa(index))
+	}
+	if additionalSpec, ok := schemaSpec.AdditionalProperties.(map[string]interface{}); ok {
+		result = appendSpecError(&s, result, positions, additionalSpec, "/additional_properties")
+	}
+	for index, conditionalSpec := range schemaSpec.Conditions {
+		branches := []map[string]interface{}{conditionalSpec.If, conditionalSpec.Then, conditionalSpec.Else}
+		for branchIndex, key := range []string{"if", "then", "else"} {
+			fields, _ := branches[branchIndex]["fields"].([]interface{})
+			for fieldIndex, field := range fields {
+				if fieldSpec, ok := field.(map[string]interface{}); ok {
+					pointer := "/conditions/" + strconv.Itoa(index) + "/" + key + "/fields/" + strconv.Itoa(fieldIndex)
+					result = appendSpecError(&s, result, positions, fieldSpec, pointer)
+				}
+			}
+		}
+	}
+	if result == nil {
+		return err
+	}
+	return errors.Wrap(result, "could not unmarshal file given to Schema")
+}
+
+func appendSpecError(s *Schema, result error, positions documentPositions, fieldSpec map[string]interface{}, pointer string) error {
+	_, err := s.getField(fieldSpec)
+	if err == nil {
+		return result
+	}
+	pointer, err = invalidFieldSpec(s, fieldSpec, pointer, err)
+	position := positions.find(pointer)
+	return multierror.Append(result, &SpecError{File: position.file, Line: position.line, Column: position.column, Path: pointer, Err: err})
+}
+
+// invalidFieldSpec returns the pointer and error of the deepest invalid field definition in an invalid field spec,
+// since an invalid nested field makes all of its parents invalid too.
+func invalidFieldSpec(s *Schema, fieldSpec map[string]interface{}, pointer string, err error) (string, error) {
+	for _, child := range childFieldSpecs(fieldSpec, pointer) {
+		_, childErr := s.getField(child.spec)
+		if childErr != nil {
+			return invalidFieldSpec(s, child.spec, child.pointer, childErr)
+		}
+	}
+	return pointer, err
+}
+
+type childFieldSpec struct {
+	pointer string
+	spec    map[string]interface{}
+}
+
+// childFieldSpecs returns nested field specs of a field spec in their order in the spec.
+func childFieldSpecs(fieldSpec map[string]interface{}, pointer string) []childFieldSpec {
+	var children []childFieldSpec
+	addField := func(field interface{}, fieldPointer string) {
+		if spec, ok := field.(map[string]interface{}); ok {
+			children = append(children, childFieldSpec{pointer: fieldPointer, spec: spec})
+		}
+	}
+	addFields := func(fields interface{}, fieldsPointer string) {
+		fieldList, _ := fields.([]interface{})
+		for index, field := range fieldList {
+			addField(field, fieldsPointer+"/"+strconv.Itoa(index))
+		}
+	}
+
+	addField(fieldSpec["items"], pointer+"/items")
+	addField(fieldSpec["field"], pointer+"/field")
+	addFields(fieldSpec["fields"], pointer+"/fields")
+	if schema, ok := fieldSpec["schema"].(map[string]interface{}); ok {
+		addFields(schema["fields"], pointer+"/schema/fields")
+		addField(schema["additional_properties"], pointer+"/schema/additional_properties")
+	}
+	return children
+}
+
+// sortedKeys returns the keys of a map of specs in sorted order.
+func sortedKeys(specs map[string]map[string]interface{}) []string {
+	keys := make([]string, 0, len(specs))
+	for key := range specs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+package vjson
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"strings"
+)
+
+const (
+	// DefaultMaxLineSize is the max size of a line in ValidateStream if StreamOptions.MaxLineSize is not set.
+	DefaultMaxLineSize = 16 * 1024 * 1024
+
+	streamReadSize = 64 * 1024
+	lineSizeRule   = "line_size"
+)
+
+// StreamOptions configures ValidateStream.
+type StreamOptions struct {
+	// MaxErrors stops the validation after the line which makes the number of validation errors reach it.
+	// zero means no limit.
+	MaxErrors int
+	// MaxLineSize is the max size of a line in bytes. longer lines are reported as invalid without being kept in memory.
+	// DefaultMaxLineSize is used if it is zero.
+	MaxLineSize int
+	// Valid and Invalid receive valid and invalid lines if they are set. every written line ends with a newline.
+	// lines which are longer than MaxLineSize are not written.
+	Valid   io.Writer
+	Invalid io.Writer
+	// OnError is called with the errors of every invalid line. errors are not kept in StreamResult if it is set,
+	// so memory usage does not depend on the number of invalid lines.
+	OnError func(StreamError)
+}
+
+// StreamError contains the validation errors of a line of a stream.
+type StreamError struct {
+	// Line is the number of the line in the stream, starting at 1.
+	Line int `json:"line"`
+	// Offset is the byte offset of the start of the line in the stream.
+	Offset int64             `json:"offset"`
+	Errors []ValidationError `json:"errors"`
+}
+
+func (e StreamError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, validationError := range e.Errors {
+		messages = append(messages, validationError.Error())
+	}
+	return fmt.Sprintf("line %d (offset %d): %s", e.Line, e.Offset, strings.Join(messages, "; "))
+}
+
+// StreamResult is the summary of a ValidateStream call.
+type StreamResult struct {
+	// Lines is the number of validated lines. empty lines are not validated.
+	Lines   int
+	Valid   int
+	Invalid int
+	// Errors are the errors of invalid lines, if StreamOptions.OnError is not set.
+	Errors []StreamError
+	// Stopped is true if the validation is stopped before the end of the stream because of StreamOptions.MaxErrors.
+	Stopped bool
+}
+
+// ValidateStream validates a stream of newline delimited json objects (NDJSON or JSON Lines).
+// every line is validated independently and only one line is kept in memory at a time.
+// the returned error is only for reading the stream or writing lines to StreamOptions writers.
+func (s *Schema) ValidateStream(r io.Reader, opts StreamOptions) (*StreamResult, error) {
+	maxLineSize := opts.MaxLineSize
+	if maxLineSize <= 0 {
+		maxLineSize = DefaultMaxLineSize
+	}
+	readSize := streamReadSize
+	if readSize > maxLineSize {
+		readSize = maxLineSize
+	}
+	reader := bufio.NewReaderSize(r, readSize)
+
+	result := &StreamResult{}
+	errorCount := 0
+	var offset int64
+	var buffer []byte
+	for lineNumber := 1; ; lineNumber++ {
+		lineOffset := offset
+		line, size, tooLong, err := readStreamLine(reader, buffer[:0], maxLineSize)
+		buffer = line
+		offset += size
+		if err != nil && err != io.EOF {
+			return result, errors.Wrapf(err, "could not read line %d", lineNumber)
+		}
+
+		content := bytes.TrimSpace(line)
+		if len(content) > 0 || tooLong {
+			result.Lines++
+			var lineErrors []ValidationError
+			if tooLong {
+				lineErrors = []ValidationError{*newValidationError("", lineSizeRule, maxLineSize, nil, "line is longer than %d bytes", maxLineSize)}
+			} else {
+				lineErrors = toValidationErrors(s.ValidateBytes(content))
+			}
+
+			if len(lineErrors) == 0 {
+				result.Valid++
+				if writeErr := writeStreamLine(opts.Valid, content); writeErr != nil {
+					return result, errors.Wrapf(writeErr, "could not write valid line %d", lineNumber)
+				}
+			} else {
+				result.Invalid++
+				if !tooLong {
+					if writeErr := writeStreamLine(opts.Invalid, content); writeErr != nil {
+						return result, errors.Wrapf(writeErr, "could not write invalid line %d", lineNumber)
+					}
+				}
+				streamError := StreamError{Line: lineNumber, Offset: lineOffset, Errors: lineErrors}
+				if opts.OnError != nil {
+					opts.OnError(streamError)
+				} else {
+					result.Errors = append(result.Errors, streamError)
+				}
+
+				errorCount += len(lineErrors)
+				if opts.MaxErrors > 0 && errorCount >= opts.MaxErrors {
+					_, peekErr := reader.Peek(1)
+					result.Stopped = peekErr != io.EOF
+					return result, nil
+				}
+			}
+		}
+
+		if err == io.EOF {
+			return result, nil
+		}
+	}
+}
+
+// readStreamLine appends the next line of reader, including its newline, to line and returns it with the number of
+// read bytes. if the line is longer than maxLineSize, the rest of it is read without being kept and tooLong is true.
+func readStreamLine(reader *bufio.Reader, line []byte, maxLineSize int) ([]byte, int64, bool, error) {
+	var size int64
+	tooLong := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		size += int64(len(chunk))
+		if !tooLong && len(line)+len(bytes.TrimRight(chunk, "\r\n")) > maxLineSize {
+			tooLong = true
+			line = line[:0]
+		}
+		if !tooLong {
+			line = append(line, chunk...)
+		}
+		if err != bufio.ErrBufferFull {
+			return line, size, tooLong, err
+		}
+	}
+}
+
+func writeStreamLine(w io.Writer, line []byte) error {
+	if w == nil {
+		return nil
+	}
+	_, err := w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,107 @@
+package vjson
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failure")
+}
+
+func TestSchema_ValidateStream(t *testing.T) {
+	schema := NewSchema(
+		String("name").Required(),
+		Integer("age").Min(18),
+	)
+	input := `{"name": "a", "age": 20}
+{"age": 10}
+
+{"name": "b"}
+not json
+{"name": "c", "age": 1}`
+
+	t.Run("all", func(t *testing.T) {
+		var valid, invalid bytes.Buffer
+		result, err := schema.ValidateStream(strings.NewReader(input), StreamOptions{Valid: &valid, Invalid: &invalid})
+		assert.Nil(t, err)
+		assert.Equal(t, 5, result.Lines)
+		assert.Equal(t, 2, result.Valid)
+		assert.Equal(t, 3, result.Invalid)
+		assert.False(t, result.Stopped)
+
+		assert.Len(t, result.Errors, 3)
+		assert.Equal(t, 2, result.Errors[0].Line)
+		assert.Equal(t, int64(25), result.Errors[0].Offset)
+		assert.Len(t, result.Errors[0].Errors, 2)
+		assert.Equal(t, "/name", result.Errors[0].Errors[0].Path)
+		assert.Equal(t, "/age", result.Errors[0].Errors[1].Path)
+
+		assert.Equal(t, 5, result.Errors[1].Line)
+		assert.Equal(t, int64(52), result.Errors[1].Offset)
+		assert.Equal(t, "json", result.Errors[1].Errors[0].Rule)
+		assert.Equal(t, "line 5 (offset 52): could not parse json input.", result.Errors[1].Error())
+
+		assert.Equal(t, 6, result.Errors[2].Line)
+
+		assert.Equal(t, "{\"name\": \"a\", \"age\": 20}\n{\"name\": \"b\"}\n", valid.String())
+		assert.Equal(t, "{\"age\": 10}\nnot json\n{\"name\": \"c\", \"age\": 1}\n", invalid.String())
+	})
+	t.Run("max_errors", func(t *testing.T) {
+		result, err := schema.ValidateStream(strings.NewReader(input), StreamOptions{MaxErrors: 3})
+		assert.Nil(t, err)
+		assert.True(t, result.Stopped)
+		assert.Equal(t, 4, result.Lines)
+		assert.Len(t, result.Errors, 2)
+
+		result, err = schema.ValidateStream(strings.NewReader(input), StreamOptions{MaxErrors: 4})
+		assert.Nil(t, err)
+		assert.False(t, result.Stopped)
+		assert.Equal(t, 5, result.Lines)
+	})
+	t.Run("on_error", func(t *testing.T) {
+		var lines []int
+		result, err := schema.ValidateStream(strings.NewReader(input), StreamOptions{OnError: func(e StreamError) {
+			lines = append(lines, e.Line)
+		}})
+		assert.Nil(t, err)
+		assert.Len(t, result.Errors, 0)
+		assert.Equal(t, []int{2, 5, 6}, lines)
+	})
+	t.Run("max_line_size", func(t *testing.T) {
+		long := `{"name": "` + strings.Repeat("x", 100) + `"}`
+		stream := "{\"name\": \"a\"}\r\n" + long + "\n{\"name\": \"b\"}\n"
+		var valid bytes.Buffer
+		result, err := schema.ValidateStream(strings.NewReader(stream), StreamOptions{MaxLineSize: 20, Valid: &valid})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Lines)
+		assert.Equal(t, 2, result.Valid)
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, 2, result.Errors[0].Line)
+		assert.Equal(t, int64(15), result.Errors[0].Offset)
+		assert.Equal(t, "line_size", result.Errors[0].Errors[0].Rule)
+		assert.Equal(t, "{\"name\": \"a\"}\n{\"name\": \"b\"}\n", valid.String())
+	})
+	t.Run("read_error", func(t *testing.T) {
+		_, err := schema.ValidateStream(failingReader{}, StreamOptions{})
+		assert.NotNil(t, err)
+	})
+}
+
+func BenchmarkSchema_ValidateStream(b *testing.B) {
+	schema := NewSchema(
+		String("name").Required(),
+		Integer("age").Min(18),
+	)
+	input := strings.Repeat(`{"name": "James", "age": 20}`+"\n", 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = schema.ValidateStream(strings.NewReader(input), StreamOptions{})
+	}
+}
@@ -12,6 +12,7 @@ import (
 type StringField struct {
 	name     string
 	required bool
+	nullable bool
 
 	validateMinLength bool
 	minLength         int
@@ -21,9 +22,14 @@ type StringField struct {
 
 	validateFormat bool
 	format         string
+	formatRegex    *regexp.Regexp
+	formatErr      error
 
 	validateChoices bool
 	choices         []string
+
+	validators   customValidators
+	defaultValue interface{}
 }
 
 // To Force Implementing Field interface by StringField
@@ -34,12 +40,44 @@ func (s *StringField) GetName() string {
 	return s.name
 }
 
+// Custom adds a validator function to the field, which is called with the value after the other rules of the field
+// accept it. name is the rule of its errors, and it is written in validators key when the field is marshalled.
+// if fn is nil, the function which is registered with name by RegisterValidator is used.
+func (s *StringField) Custom(name string, fn ValidatorFunc) *StringField {
+	s.validators = s.validators.add(name, fn)
+	return s
+}
+
+func (s *StringField) getValidators() customValidators {
+	return s.validators
+}
+
+// Default sets the value of the field which is used by Schema.Apply when the field is missing in a json object.
+func (s *StringField) Default(value string) *StringField {
+	s.defaultValue = value
+	return s
+}
+
+func (s *StringField) getDefault() (interface{}, bool) {
+	return s.defaultValue, s.defaultValue != nil
+}
+
 // Required is called to make a field required in a JSON
 func (s *StringField) Required() *StringField {
 	s.required = true
 	return s
 }
 
+// Nullable is called to accept null as a valid value of the field in a JSON
+func (s *StringField) Nullable() *StringField {
+	s.nullable = true
+	return s
+}
+
+func (s *StringField) isNullable() bool {
+	return s.nullable
+}
+
 // MinLength is called to set a minimum length to a string field
 func (s *StringField) MinLength(length int) *StringField {
 	if length < 0 {
@@ -64,9 +102,25 @@ func (s *StringField) MaxLength(length int) *StringField {
 func (s *StringField) Format(format string) *StringField {
 	s.format = format
 	s.validateFormat = true
+	s.compileFormat()
 	return s
 }
 
+// compileFormat compiles the regex format once, so it is not compiled for every validated value.
+func (s *StringField) compileFormat() {
+	s.formatRegex, s.formatErr = regexp.Compile(s.format)
+	if s.formatErr != nil {
+		s.formatErr = errors.Wrapf(s.formatErr, "Invalid StringField format string for field %s", s.name)
+	}
+}
+
+func (s *StringField) compile() error {
+	if s.validateFormat {
+		return s.formatErr
+	}
+	return nil
+}
+
 // Choices function is called to set valid choices of a string field in validation
 func (s *StringField) Choices(choices ...string) *StringField {
 	s.choices = choices
@@ -76,30 +130,39 @@ func (s *StringField) Choices(choices ...string) *StringField {
 
 // Validate is used for validating a value. it returns an error if the value is invalid.
 func (s *StringField) Validate(value interface{}) error {
+	err := s.validateValue(value)
+	if err != nil || value == nil {
+		return err
+	}
+	return s.validators.validate(s.name, value)
+}
+
+// validateValue validates a value with the rules of the field, except its custom validators.
+func (s *StringField) validateValue(value interface{}) error {
 	if value == nil {
 		if !s.required {
 			return nil
 		}
-		return errors.Errorf("Value for %s field is required", s.name)
+		return requiredError(s.name)
 	}
 
 	stringValue, ok := value.(string)
 
 	if !ok {
-		return errors.Errorf("Value for %s should be a string", s.name)
+		return newValidationError(s.name, typeRule, stringType, value, "Value for %s should be a string", s.name)
 	}
 
 	var result error
 
 	if s.validateMinLength {
 		if len(stringValue) < s.minLength {
-			result = multierror.Append(result, errors.Errorf("Value for %s field should have at least %d characters", s.name, s.minLength))
+			result = multierror.Append(result, newValidationError(s.name, "min_length", s.minLength, stringValue, "Value for %s field should have at least %d characters", s.name, s.minLength))
 		}
 	}
 
 	if s.validateMaxLength {
 		if len(stringValue) > s.maxLength {
-			result = multierror.Append(result, errors.Errorf("Value for %s field should have at most %d characters", s.name, s.maxLength))
+			result = multierror.Append(result, newValidationError(s.name, "max_length", s.maxLength, stringValue, "Value for %s field should have at most %d characters", s.name, s.maxLength))
 		}
 	}
 
@@ -109,20 +172,19 @@ func (s *StringField) Validate(value interface{}) error {
 				return nil
 			}
 		}
-		result = multierror.Append(result, errors.Errorf("Value for %s field should be one of: [%s] values", s.name, strings.Join(s.choices, ",")))
+		result = multierror.Append(result, newValidationError(s.name, "choices", s.choices, stringValue, "Value for %s field should be one of: [%s] values", s.name, strings.Join(s.choices, ",")))
 	}
 
 	if s.validateFormat {
-		r, err := regexp.Compile(s.format)
-		if err != nil {
-			result = multierror.Append(result, errors.Wrapf(err, "Invalid StringField format string for field %s", s.name))
+		if s.formatErr != nil {
+			result = multierror.Append(result, s.formatErr)
 			return result
 		}
 
-		isValidFormat := r.MatchString(stringValue)
+		isValidFormat := s.formatRegex.MatchString(stringValue)
 
 		if !isValidFormat {
-			result = multierror.Append(result, errors.Wrapf(err, "Invalid StringField format string for field %s", s.name))
+			result = multierror.Append(result, newValidationError(s.name, "format", s.format, stringValue, "Value for %s field should match format %s", s.name, s.format))
 		}
 	}
 
@@ -131,13 +193,16 @@ func (s *StringField) Validate(value interface{}) error {
 
 func (s *StringField) MarshalJSON() ([]byte, error) {
 	return json.Marshal(StringFieldSpec{
-		Name:      s.name,
-		Required:  s.required,
-		MinLength: s.minLength,
-		MaxLength: s.maxLength,
-		Format:    s.format,
-		Choices:   s.choices,
-		Type:      stringType,
+		Name:       s.name,
+		Required:   s.required,
+		Nullable:   s.nullable,
+		MinLength:  s.minLength,
+		MaxLength:  s.maxLength,
+		Format:     s.format,
+		Choices:    s.choices,
+		Type:       stringType,
+		Validators: s.validators.names(),
+		Default:    s.defaultValue,
 	})
 }
 
@@ -2,20 +2,24 @@ package vjson
 
 // StringFieldSpec is a type used for parsing an StringField
 type StringFieldSpec struct {
-	Name      string    `mapstructure:"name" json:"name"`
-	Type      fieldType `json:"type"`
-	Required  bool      `mapstructure:"required" json:"required,omitempty"`
-	MinLength int       `mapstructure:"min_length" json:"minLength,omitempty"`
-	MaxLength int       `mapstructure:"max_length" json:"maxLength,omitempty"`
-	Format    string    `mapstructure:"format" json:"format,omitempty"`
-	Choices   []string  `mapstructure:"choices" json:"choices,omitempty"`
+	Name       string      `mapstructure:"name" json:"name"`
+	Type       fieldType   `json:"type"`
+	Required   bool        `mapstructure:"required" json:"required,omitempty"`
+	Nullable   bool        `mapstructure:"nullable" json:"nullable,omitempty"`
+	MinLength  int         `mapstructure:"min_length" json:"minLength,omitempty"`
+	MaxLength  int         `mapstructure:"max_length" json:"maxLength,omitempty"`
+	Format     string      `mapstructure:"format" json:"format,omitempty"`
+	Choices    []string    `mapstructure:"choices" json:"choices,omitempty"`
+	Validators []string    `mapstructure:"validators" json:"validators,omitempty"`
+	Default    interface{} `mapstructure:"default" json:"default,omitempty"`
 }
 
 // NewString receives an StringFieldSpec and returns and StringField
 func NewString(spec StringFieldSpec, minLengthValidation, maxLengthValidation, formatValidation, choiceValidation bool) *StringField {
-	return &StringField{
+	field := &StringField{
 		name:              spec.Name,
 		required:          spec.Required,
+		nullable:          spec.Nullable,
 		validateMinLength: minLengthValidation,
 		minLength:         spec.MinLength,
 		validateMaxLength: maxLengthValidation,
@@ -24,5 +28,11 @@ func NewString(spec StringFieldSpec, minLengthValidation, maxLengthValidation, f
 		format:            spec.Format,
 		validateChoices:   choiceValidation,
 		choices:           spec.Choices,
+		validators:        namedValidators(spec.Validators),
+		defaultValue:      spec.Default,
 	}
+	if formatValidation {
+		field.compileFormat()
+	}
+	return field
 }
@@ -0,0 +1,405 @@
+package vjson
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const structTagKey = "vjson"
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	bigIntType = reflect.TypeOf(big.Int{})
+)
+
+// structTag is the parsed form of a vjson struct tag.
+type structTag struct {
+	name    string
+	skip    bool
+	options map[string]string
+}
+
+// structTagOptions are the options which are supported in vjson struct tags.
+var structTagOptions = map[string]bool{
+	"required": true, "nullable": true, "min": true, "max": true, "min_length": true, "max_length": true,
+	"positive": true, "negative": true, "multiple_of": true, "choices": true, "value": true, "format": true,
+}
+
+// parseStructTag parses a tag like `vjson:"name,required,min=1,max=10,format=^a"`.
+// format option takes the rest of the tag, so a format regex could contain commas if it is the last option.
+// fields with `json:"-"` tag are skipped like in json encoding, and unknown options are reported as an error.
+func parseStructTag(field reflect.StructField) (structTag, error) {
+	tag := structTag{options: make(map[string]string)}
+	value, found := field.Tag.Lookup(structTagKey)
+	if value == "-" || field.Tag.Get("json") == "-" {
+		tag.skip = true
+		return tag, nil
+	}
+	if found {
+		parts := strings.Split(value, ",")
+		tag.name = parts[0]
+		for index := 1; index < len(parts); index++ {
+			option := parts[index]
+			if strings.HasPrefix(option, "format=") {
+				tag.options["format"] = strings.TrimPrefix(strings.Join(parts[index:], ","), "format=")
+				break
+			}
+			key := option
+			optionValue := ""
+			if separator := strings.Index(option, "="); separator >= 0 {
+				key = option[:separator]
+				optionValue = option[separator+1:]
+			}
+			if !structTagOptions[key] {
+				return tag, errors.Errorf("unknown vjson tag option %q", key)
+			}
+			tag.options[key] = optionValue
+		}
+	}
+	if tag.name == "" {
+		tag.name = jsonFieldName(field)
+	}
+	return tag, nil
+}
+
+// jsonFieldName returns the name of a struct field in its json encoding.
+func jsonFieldName(field reflect.StructField) string {
+	jsonTag := field.Tag.Get("json")
+	if name := strings.Split(jsonTag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// SchemaFromStruct creates a Schema from a struct, or a pointer to a struct.
+// fields are created from types of struct fields and are customized with a vjson tag, e.g.
+// `vjson:"name,required,min=1,max=10,format=^a"`. supported options are:
+// required, nullable, min, max, min_length, max_length, positive, negative, multiple_of, choices (separated with |),
+// value and format.
+// min and max are lengths for strings and slices. fields with `vjson:"-"` or `json:"-"` tag and unexported fields are
+// skipped, and unknown options or options which are not supported for the type of a field are reported as an error.
+func SchemaFromStruct(v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.Errorf("SchemaFromStruct needs a struct, got %T", v)
+	}
+	schema, err := structSchema(t, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func structSchema(t reflect.Type, visiting map[reflect.Type]bool) (Schema, error) {
+	fields, err := structFields(t, visiting)
+	if err != nil {
+		return Schema{}, err
+	}
+	return NewSchema(fields...), nil
+}
+
+// structFields creates fields of a struct type. types which are being visited are kept in visiting, so a type which
+// contains itself, directly or by embedding, is reported as an error.
+func structFields(t reflect.Type, visiting map[reflect.Type]bool) ([]Field, error) {
+	if visiting[t] {
+		return nil, errors.Errorf("recursive type %s is not supported", t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	fields := make([]Field, 0, t.NumField())
+	for index := 0; index < t.NumField(); index++ {
+		structField := t.Field(index)
+		tag, err := parseStructTag(structField)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create field for %s.%s", t.Name(), structField.Name)
+		}
+		if tag.skip {
+			continue
+		}
+
+		// fields of embedded structs are promoted, like in json encoding
+		if structField.Anonymous && !hasName(structField) {
+			embedded := structField.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				embeddedFields, err := structFields(embedded, visiting)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, embeddedFields...)
+				continue
+			}
+		}
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		field, err := typeField(tag.name, structField.Type, tag.options, visiting)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create field for %s.%s", t.Name(), structField.Name)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// hasName reports whether an embedded struct field is named by a tag, so it is not promoted.
+func hasName(field reflect.StructField) bool {
+	if _, found := field.Tag.Lookup(structTagKey); found && strings.Split(field.Tag.Get(structTagKey), ",")[0] != "" {
+		return true
+	}
+	return strings.Split(field.Tag.Get("json"), ",")[0] != ""
+}
+
+// typeField creates a field for a Go type with the given tag options.
+func typeField(name string, t reflect.Type, options map[string]string, visiting map[reflect.Type]bool) (Field, error) {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+	if _, ok := options["nullable"]; ok {
+		nullable = true
+	}
+	_, required := options["required"]
+
+	switch {
+	case t == timeType:
+		field := String(name)
+		return applyStringOptions(field, options, required, nullable)
+	case t == bigIntType:
+		return applyIntegerOptions(Integer(name).Big(), options, required, nullable)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// []byte is encoded as a base64 string
+		field := String(name)
+		return applyStringOptions(field, options, required, nullable)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return applyIntegerOptions(Integer(name), options, required, nullable)
+	case reflect.Uint, reflect.Uint64:
+		// unsigned 64-bit integers do not fit in int64
+		return applyIntegerOptions(Integer(name).Positive().Big(), options, required, nullable)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return applyIntegerOptions(Integer(name).Positive(), options, required, nullable)
+	case reflect.Float32, reflect.Float64:
+		return applyFloatOptions(Float(name), options, required, nullable)
+	case reflect.String:
+		return applyStringOptions(String(name), options, required, nullable)
+	case reflect.Bool:
+		err := checkOptions(options, "boolean", "value")
+		if err != nil {
+			return nil, err
+		}
+		field := Boolean(name)
+		if value, ok := options["value"]; ok {
+			boolValue, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid value option %s", value)
+			}
+			field.ShouldBe(boolValue)
+		}
+		field.required = required
+		field.nullable = nullable
+		return field, nil
+	case reflect.Slice, reflect.Array:
+		err := checkOptions(options, "array", "min", "max", "min_length", "max_length")
+		if err != nil {
+			return nil, err
+		}
+		items, err := typeField(name, t.Elem(), map[string]string{}, visiting)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create items field")
+		}
+		field := Array(name, items)
+		if value, ok := lengthOption(options, "min_length", "min"); ok {
+			length, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid min length option %s", value)
+			}
+			field.MinLength(length)
+		}
+		if value, ok := lengthOption(options, "max_length", "max"); ok {
+			length, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid max length option %s", value)
+			}
+			field.MaxLength(length)
+		}
+		field.required = required
+		field.nullable = nullable
+		return field, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, errors.Errorf("map key type %s is not supported", t.Key())
+		}
+		err := checkOptions(options, "object")
+		if err != nil {
+			return nil, err
+		}
+		values, err := typeField(name, t.Elem(), map[string]string{}, visiting)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create values field")
+		}
+		field := Object(name, NewSchema().AdditionalProperties(values))
+		field.required = required
+		field.nullable = nullable
+		return field, nil
+	case reflect.Struct:
+		err := checkOptions(options, "object")
+		if err != nil {
+			return nil, err
+		}
+		schema, err := structSchema(t, visiting)
+		if err != nil {
+			return nil, err
+		}
+		field := Object(name, schema)
+		field.required = required
+		field.nullable = nullable
+		return field, nil
+	}
+	return nil, errors.Errorf("type %s is not supported", t)
+}
+
+// checkOptions reports an error for tag options which are not supported for fields of the given type.
+// required and nullable options are supported for all fields.
+func checkOptions(options map[string]string, fieldType string, supported ...string) error {
+	allowed := map[string]bool{"required": true, "nullable": true}
+	for _, key := range supported {
+		allowed[key] = true
+	}
+	unsupported := make([]string, 0)
+	for key := range options {
+		if !allowed[key] {
+			unsupported = append(unsupported, key)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return errors.Errorf("vjson tag option %q is not supported for %s fields", unsupported[0], fieldType)
+	}
+	return nil
+}
+
+// lengthOption returns the first present option of the given keys.
+func lengthOption(options map[string]string, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := options[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func applyIntegerOptions(field *IntegerField, options map[string]string, required, nullable bool) (Field, error) {
+	err := checkOptions(options, "integer", "min", "max", "positive", "negative", "multiple_of")
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := options["min"]; ok {
+		min, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid min option %s", value)
+		}
+		field.Min(min)
+	}
+	if value, ok := options["max"]; ok {
+		max, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid max option %s", value)
+		}
+		field.Max(max)
+	}
+	if _, ok := options["positive"]; ok {
+		field.Positive()
+	}
+	if _, ok := options["negative"]; ok {
+		field.Negative()
+	}
+	if value, ok := options["multiple_of"]; ok {
+		multipleOf, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid multiple_of option %s", value)
+		}
+		field.MultipleOf(multipleOf)
+	}
+	field.required = required
+	field.nullable = nullable
+	return field, nil
+}
+
+func applyFloatOptions(field *FloatField, options map[string]string, required, nullable bool) (Field, error) {
+	err := checkOptions(options, "float", "min", "max", "positive", "negative")
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := options["min"]; ok {
+		min, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid min option %s", value)
+		}
+		field.Min(min)
+	}
+	if value, ok := options["max"]; ok {
+		max, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid max option %s", value)
+		}
+		field.Max(max)
+	}
+	if _, ok := options["positive"]; ok {
+		field.Positive()
+	}
+	if _, ok := options["negative"]; ok {
+		field.Negative()
+	}
+	field.required = required
+	field.nullable = nullable
+	return field, nil
+}
+
+func applyStringOptions(field *StringField, options map[string]string, required, nullable bool) (Field, error) {
+	err := checkOptions(options, "string", "min", "max", "min_length", "max_length", "choices", "format")
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := lengthOption(options, "min_length", "min"); ok {
+		length, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid min length option %s", value)
+		}
+		field.MinLength(length)
+	}
+	if value, ok := lengthOption(options, "max_length", "max"); ok {
+		length, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid max length option %s", value)
+		}
+		field.MaxLength(length)
+	}
+	if value, ok := options["choices"]; ok {
+		field.Choices(strings.Split(value, "|")...)
+	}
+	if value, ok := options["format"]; ok {
+		field.Format(value)
+		if field.formatErr != nil {
+			return nil, field.formatErr
+		}
+	}
+	field.required = required
+	field.nullable = nullable
+	return field, nil
+}
@@ -0,0 +1,183 @@
+package vjson
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type structSchemaAddress struct {
+	City string `json:"city" vjson:",required,min=2"`
+	Zip  string `vjson:"zip,format=^[0-9]{5}(,[0-9]{4})?$"`
+}
+
+type structSchemaBase struct {
+	ID uint64 `json:"id" vjson:",required"`
+}
+
+type structSchemaUser struct {
+	structSchemaBase
+	Name      string                 `vjson:"name,required,min=2,max=10"`
+	Age       int                    `json:"age" vjson:",min=18,max=99"`
+	Score     float64                `json:"score" vjson:",positive,max=10.5"`
+	Role      string                 `json:"role" vjson:",choices=admin|user"`
+	Active    bool                   `json:"active" vjson:",value=true"`
+	Nickname  *string                `json:"nickname"`
+	Tags      []string               `json:"tags" vjson:",min=1"`
+	Address   structSchemaAddress    `json:"address" vjson:",required"`
+	Addresses []*structSchemaAddress `json:"addresses"`
+	Labels    map[string]int         `json:"labels"`
+	CreatedAt time.Time              `json:"created_at"`
+	Ignored   string                 `vjson:"-"`
+	internal  string
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		schema, err := SchemaFromStruct(&structSchemaUser{})
+		assert.Nil(t, err)
+		assert.Len(t, schema.Fields, 12)
+
+		id := schema.Fields[0].(*IntegerField)
+		assert.Equal(t, "id", id.name)
+		assert.Equal(t, true, id.required)
+		assert.Equal(t, true, id.signValidation)
+
+		name := schema.Fields[1].(*StringField)
+		assert.Equal(t, "name", name.name)
+		assert.Equal(t, true, name.required)
+		assert.Equal(t, 2, name.minLength)
+		assert.Equal(t, 10, name.maxLength)
+
+		age := schema.Fields[2].(*IntegerField)
+		assert.Equal(t, "age", age.name)
+		assert.Equal(t, 18, age.min)
+		assert.Equal(t, 99, age.max)
+
+		score := schema.Fields[3].(*FloatField)
+		assert.Equal(t, 10.5, score.max)
+		assert.Equal(t, true, score.positive)
+
+		assert.Equal(t, []string{"admin", "user"}, schema.Fields[4].(*StringField).choices)
+		assert.Equal(t, true, schema.Fields[5].(*BooleanField).valueValidation)
+		assert.Equal(t, true, schema.Fields[6].(*StringField).nullable)
+		assert.Equal(t, 1, schema.Fields[7].(*ArrayField).minLength)
+
+		address := schema.Fields[8].(*ObjectField)
+		assert.Equal(t, true, address.required)
+		assert.Len(t, address.schema.Fields, 2)
+		assert.Equal(t, "^[0-9]{5}(,[0-9]{4})?$", address.schema.Fields[1].(*StringField).format)
+
+		addresses := schema.Fields[9].(*ArrayField)
+		assert.Equal(t, true, addresses.items.(*ObjectField).nullable)
+
+		assert.Equal(t, additionalPropertiesValidate, schema.Fields[10].(*ObjectField).schema.additionalProperties)
+		assert.Equal(t, "created_at", schema.Fields[11].GetName())
+	})
+	t.Run("validation", func(t *testing.T) {
+		schema, err := SchemaFromStruct(structSchemaUser{})
+		assert.Nil(t, err)
+
+		err = schema.ValidateString(`{"id": 1, "name": "John", "age": 20, "tags": ["a"], "address": {"city": "Paris", "zip": "12345,1234"}, "labels": {"a": 1}, "nickname": null}`)
+		assert.Nil(t, err)
+
+		errs := schema.ValidateDetailed([]byte(`{"id": -1, "name": "J", "role": "root", "address": {"city": "P"}, "labels": {"a": "b"}}`))
+		assert.Len(t, errs, 5)
+		assert.Equal(t, "/id", errs[0].Path)
+		assert.Equal(t, "/name", errs[1].Path)
+		assert.Equal(t, "/role", errs[2].Path)
+		assert.Equal(t, "/address/city", errs[3].Path)
+		assert.Equal(t, "/labels/a", errs[4].Path)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := SchemaFromStruct(1)
+		assert.NotNil(t, err)
+
+		_, err = SchemaFromStruct(nil)
+		assert.NotNil(t, err)
+
+		_, err = SchemaFromStruct(struct {
+			Age int `vjson:"age,min=a"`
+		}{})
+		assert.NotNil(t, err)
+
+		_, err = SchemaFromStruct(struct {
+			Value interface{} `vjson:"value"`
+		}{})
+		assert.NotNil(t, err)
+
+		_, err = SchemaFromStruct(struct {
+			Code string `vjson:"code,format=[a-"`
+		}{})
+		assert.NotNil(t, err)
+
+		_, err = SchemaFromStruct(struct {
+			A string `vjson:"a,requird"`
+		}{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `unknown vjson tag option "requird"`)
+	})
+	t.Run("unsupported_options", func(t *testing.T) {
+		_, err := SchemaFromStruct(struct {
+			Age int `vjson:"age,format=^[0-9]+$"`
+		}{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `vjson tag option "format" is not supported for integer fields`)
+
+		_, err = SchemaFromStruct(struct {
+			Price float64 `vjson:"price,multiple_of=2"`
+		}{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `vjson tag option "multiple_of" is not supported for float fields`)
+
+		_, err = SchemaFromStruct(struct {
+			Price float64 `vjson:"price,choices=1|2"`
+		}{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `vjson tag option "choices" is not supported for float fields`)
+
+		_, err = SchemaFromStruct(struct {
+			Tags []string `vjson:"tags,required,positive"`
+		}{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), `vjson tag option "positive" is not supported for array fields`)
+	})
+	t.Run("json_skip", func(t *testing.T) {
+		schema, err := SchemaFromStruct(struct {
+			Name   string `json:"name"`
+			Secret string `json:"-"`
+			Dash   string `json:"-,"`
+		}{})
+		assert.Nil(t, err)
+		assert.Len(t, schema.Fields, 2)
+		assert.Equal(t, "name", schema.Fields[0].GetName())
+		assert.Equal(t, "-", schema.Fields[1].GetName())
+	})
+	t.Run("recursive", func(t *testing.T) {
+		type node struct {
+			Children []node `json:"children"`
+		}
+		_, err := SchemaFromStruct(node{})
+		assert.NotNil(t, err)
+	})
+	t.Run("recursive_embedded", func(t *testing.T) {
+		type node struct {
+			*node
+			X int `json:"x"`
+		}
+		_, err := SchemaFromStruct(node{})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "recursive type")
+	})
+	t.Run("integers", func(t *testing.T) {
+		schema, err := SchemaFromStruct(struct {
+			ID     uint64   `json:"id"`
+			Amount *big.Int `json:"amount" vjson:",multiple_of=5"`
+		}{})
+		assert.Nil(t, err)
+		assert.Nil(t, schema.ValidateString(`{"id": 18446744073709551615, "amount": 100000000000000000000}`))
+		assert.NotNil(t, schema.ValidateString(`{"id": -1}`))
+		assert.NotNil(t, schema.ValidateString(`{"amount": 12}`))
+	})
+}
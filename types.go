@@ -10,6 +10,21 @@ const (
 	booleanType fieldType = "boolean"
 	objectType  fieldType = "object"
 	nullType    fieldType = "null"
+	oneOfType   fieldType = "one_of"
+	anyOfType   fieldType = "any_of"
+	allOfType   fieldType = "all_of"
+	notType     fieldType = "not"
+	decimalType fieldType = "decimal"
 )
 
+// isBuiltinType reports whether a field type is parsed by vjson itself, rather than a registered factory.
+func isBuiltinType(t fieldType) bool {
+	switch t {
+	case integerType, floatType, stringType, arrayType, booleanType, objectType, nullType,
+		oneOfType, anyOfType, allOfType, notType, refType, decimalType:
+		return true
+	}
+	return false
+}
+
 const typeKey = "type"
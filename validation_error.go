@@ -0,0 +1,144 @@
+package vjson
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// ValidationError describes a single validation failure of a JSON document.
+type ValidationError struct {
+	// Path is the location of the invalid value as an RFC 6901 JSON Pointer, e.g. /tags/3
+	Path string `json:"path"`
+	// Field is the name of the field which rejected the value
+	Field string `json:"field"`
+	// Rule is the keyword of the failed validation rule, e.g. min, format or choices
+	Rule string `json:"rule"`
+	// Expected is the value required by the rule
+	Expected interface{} `json:"expected,omitempty"`
+	// Actual is the value which was found in the document
+	Actual interface{} `json:"actual,omitempty"`
+	// Message is a human readable description of the failure
+	Message string `json:"message"`
+	// Causes are the failures of the nested fields of a combinator field, e.g. failed branches of a one_of field
+	Causes []ValidationError `json:"causes,omitempty"`
+	// RelatedPaths are the locations of other values which the invalid value is compared with, e.g. the end of a range
+	RelatedPaths []string `json:"related_paths,omitempty"`
+	// Line and Column are the position of the invalid value in a YAML or TOML document, starting at 1.
+	// they are zero for json documents.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+const (
+	requiredRule = "required"
+	nullableRule = "nullable"
+	typeRule     = "type"
+	invalidRule  = "invalid"
+	jsonRule     = "json"
+)
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	message := e.Message
+	if e.Path != "" {
+		message = fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, message)
+	}
+	return message
+}
+
+func newValidationError(field, rule string, expected, actual interface{}, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		Field:    field,
+		Rule:     rule,
+		Expected: expected,
+		Actual:   actual,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func requiredError(field string) *ValidationError {
+	return newValidationError(field, requiredRule, true, nil, "Value for %s field is required", field)
+}
+
+func invalidJSONError() *ValidationError {
+	return newValidationError("", jsonRule, nil, nil, "could not parse json input.")
+}
+
+// escapePointerToken escapes a reference token of a JSON Pointer according to RFC 6901.
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// prefixPath prepends a JSON Pointer reference token to the path of every validation error in err.
+func prefixPath(err error, token string) error {
+	if err == nil {
+		return nil
+	}
+	var result error
+	for _, validationError := range toValidationErrors(err) {
+		validationError := prefixValidationError(validationError, "/"+escapePointerToken(token))
+		result = multierror.Append(result, &validationError)
+	}
+	return result
+}
+
+func prefixValidationError(validationError ValidationError, prefix string) ValidationError {
+	validationError.Path = prefix + validationError.Path
+	if len(validationError.RelatedPaths) > 0 {
+		relatedPaths := make([]string, 0, len(validationError.RelatedPaths))
+		for _, relatedPath := range validationError.RelatedPaths {
+			relatedPaths = append(relatedPaths, prefix+relatedPath)
+		}
+		validationError.RelatedPaths = relatedPaths
+	}
+	if len(validationError.Causes) > 0 {
+		causes := make([]ValidationError, 0, len(validationError.Causes))
+		for _, cause := range validationError.Causes {
+			causes = append(causes, prefixValidationError(cause, prefix))
+		}
+		validationError.Causes = causes
+	}
+	return validationError
+}
+
+// prefixIndex is like prefixPath but it receives the index of an array item.
+func prefixIndex(err error, index int) error {
+	return prefixPath(err, strconv.Itoa(index))
+}
+
+// ValidationErrors flattens an error returned by a validation into a list of ValidationError.
+// nested and wrapped multierrors are flattened too, and errors which are not a ValidationError are converted with an
+// invalid rule.
+func ValidationErrors(err error) []ValidationError {
+	return toValidationErrors(err)
+}
+
+func toValidationErrors(err error) []ValidationError {
+	if err == nil {
+		return nil
+	}
+	// a multierror is checked before errors.As, since errors.As finds only the first ValidationError of a multierror
+	merr, ok := err.(*multierror.Error)
+	if !ok && !errors.As(err, &merr) {
+		var validationError *ValidationError
+		if errors.As(err, &validationError) {
+			return []ValidationError{*validationError}
+		}
+		return []ValidationError{{
+			Rule:    invalidRule,
+			Message: err.Error(),
+		}}
+	}
+
+	result := make([]ValidationError, 0, len(merr.Errors))
+	for _, e := range merr.Errors {
+		result = append(result, toValidationErrors(e)...)
+	}
+	return result
+}
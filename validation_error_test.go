@@ -0,0 +1,92 @@
+package vjson
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidationError_Error(t *testing.T) {
+	err := ValidationError{Path: "/foo", Message: "Value for foo should be a string"}
+	assert.Equal(t, "/foo: Value for foo should be a string", err.Error())
+
+	err = ValidationError{Message: "could not parse json input."}
+	assert.Equal(t, "could not parse json input.", err.Error())
+}
+
+func TestSchema_ValidateDetailed(t *testing.T) {
+	schema := NewSchema(
+		Integer("age").Min(18),
+		String("email").Format("^.+@.+$"),
+		Array("tags", String("tag").Choices("a", "b")),
+		Object("address", NewSchema(
+			String("city").Required(),
+		)),
+		String("a/b"),
+	)
+
+	t.Run("valid", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"age": 20, "tags": ["a"], "address": {"city": "Paris"}}`))
+		assert.Len(t, errs, 0)
+	})
+	t.Run("invalid_json", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{{`))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "", errs[0].Path)
+		assert.Equal(t, "json", errs[0].Rule)
+	})
+	t.Run("rules", func(t *testing.T) {
+		errs := schema.ValidateDetailed([]byte(`{"age": 10, "email": "foo", "tags": ["a", "a", "a", "c"], "address": {}, "a/b": 1}`))
+		assert.Len(t, errs, 5)
+
+		assert.Equal(t, ValidationError{
+			Path:     "/age",
+			Field:    "age",
+			Rule:     "min",
+			Expected: 18,
+			Actual:   10,
+			Message:  "Value for age should be at least 18",
+		}, errs[0])
+
+		assert.Equal(t, "/email", errs[1].Path)
+		assert.Equal(t, "format", errs[1].Rule)
+		assert.Equal(t, "foo", errs[1].Actual)
+
+		assert.Equal(t, "/tags/3", errs[2].Path)
+		assert.Equal(t, "tag", errs[2].Field)
+		assert.Equal(t, "choices", errs[2].Rule)
+
+		assert.Equal(t, "/address/city", errs[3].Path)
+		assert.Equal(t, "required", errs[3].Rule)
+
+		assert.Equal(t, "/a~1b", errs[4].Path)
+		assert.Equal(t, "type", errs[4].Rule)
+		assert.Equal(t, stringType, errs[4].Expected)
+	})
+}
+
+func TestToValidationErrors(t *testing.T) {
+	assert.Nil(t, toValidationErrors(nil))
+
+	errs := toValidationErrors(prefixPath(errors.New("custom failure"), "foo"))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "/foo", errs[0].Path)
+	assert.Equal(t, "invalid", errs[0].Rule)
+	assert.Equal(t, "custom failure", errs[0].Message)
+
+	var nested error
+	nested = multierror.Append(nested, newValidationError("b", "min", 1, 0, "b"), newValidationError("c", "max", 1, 2, "c"))
+	var err error
+	err = multierror.Append(err, newValidationError("a", "required", true, nil, "a"), nested)
+	errs = ValidationErrors(fmt.Errorf("context: %w", err))
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "required", errs[0].Rule)
+	assert.Equal(t, "min", errs[1].Rule)
+	assert.Equal(t, "max", errs[2].Rule)
+
+	errs = ValidationErrors(fmt.Errorf("context: %w", newValidationError("a", "required", true, nil, "a")))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "required", errs[0].Rule)
+}
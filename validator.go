@@ -0,0 +1,198 @@
+package vjson
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"io"
+	"net/url"
+)
+
+var errMissingField = errors.New("field is nil")
+
+// Validator is a compiled Schema. it is created by Schema.Compile, which checks the whole schema once,
+// so problems like invalid string formats are reported before any json object is validated.
+// a Validator is safe for concurrent use.
+type Validator struct {
+	// schema is the schema which the Validator is compiled from.
+	schema Schema
+	// compiled is a copy of the schema whose nested schemas keep their compiled state, so changes to the fields of
+	// schema after Compile do not make it stale.
+	compiled Schema
+}
+
+// compiledSchema is the state of a schema which is computed once by Compile instead of in every validation.
+type compiledSchema struct {
+	// indexes are the indexes of the schema fields by their names.
+	indexes map[string][]int
+	// declared are the names of the properties which are declared in schema fields and conditionals.
+	declared map[string]struct{}
+}
+
+// Compile checks the schema and returns a Validator for it.
+// it returns an error if a field of the schema is invalid, e.g. a string field has an invalid regex format.
+func (s Schema) Compile() (*Validator, error) {
+	err := s.compile()
+	if err != nil {
+		return nil, err
+	}
+	compiled := s
+	compiled.copyNestedFields()
+	compiled.precompute(make(map[Field]bool))
+	return &Validator{schema: s, compiled: compiled}, nil
+}
+
+// precompute sets the compiled state of the schema and its nested schemas.
+func (s *Schema) precompute(visited map[Field]bool) {
+	indexes := make(map[string][]int, len(s.Fields))
+	for index, field := range s.Fields {
+		indexes[field.GetName()] = append(indexes[field.GetName()], index)
+		precomputeField(field, visited)
+	}
+	// declared names are computed before compiled is set, since declaredNames returns them afterwards
+	s.compiled = &compiledSchema{indexes: indexes, declared: s.declaredNames()}
+
+	precomputeField(s.root, visited)
+	precomputeField(s.additionalField, visited)
+	for _, definition := range s.definitions {
+		precomputeField(definition, visited)
+	}
+	for _, conditional := range s.conditionals {
+		conditional.condition.precompute(visited)
+		if conditional.then != nil {
+			conditional.then.precompute(visited)
+		}
+		if conditional.otherwise != nil {
+			conditional.otherwise.precompute(visited)
+		}
+	}
+}
+
+// precomputeField sets the compiled state of the schemas which are nested in a field.
+func precomputeField(field Field, visited map[Field]bool) {
+	if field == nil || visited[field] {
+		return
+	}
+	visited[field] = true
+	switch f := field.(type) {
+	case *ObjectField:
+		f.schema.precompute(visited)
+	case *ArrayField:
+		precomputeField(f.items, visited)
+	case *CombinatorField:
+		for _, branch := range f.fields {
+			precomputeField(branch, visited)
+		}
+	case *RefField:
+		precomputeField(f.field, visited)
+	}
+}
+
+func (s *Schema) compile() error {
+	var result error
+	for index, field := range s.Fields {
+		if field == nil {
+			result = multierror.Append(result, errors.Wrapf(errMissingField, "field %d is invalid", index))
+			continue
+		}
+		err := compileField(field)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	if s.root != nil {
+		err := compileField(s.root)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrap(err, "root field is invalid"))
+		}
+	}
+	for _, name := range s.definitionNames() {
+		err := compileField(s.definitions[name])
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "definition %s is invalid", name))
+		}
+	}
+	for index, conditional := range s.conditionals {
+		for _, schema := range conditional.schemas() {
+			err := schema.compile()
+			if err != nil {
+				result = multierror.Append(result, errors.Wrapf(err, "condition %d is invalid", index))
+			}
+		}
+	}
+	for index, comparison := range s.comparisons {
+		err := comparison.check()
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "comparison %d is invalid", index))
+		}
+	}
+	if s.additionalProperties == additionalPropertiesValidate {
+		err := compileField(s.additionalField)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrap(err, "additional properties field is invalid"))
+		}
+	}
+	return result
+}
+
+// Schema returns the schema which the Validator is compiled from.
+func (v *Validator) Schema() Schema {
+	return v.schema
+}
+
+// ValidateBytes receives a byte array of a json object and validates it according to the compiled Schema.
+// it returns an error if the input is invalid.
+func (v *Validator) ValidateBytes(input []byte) error {
+	return v.compiled.ValidateBytes(input)
+}
+
+// ValidateString is like ValidateBytes but it receives the json object as string input.
+func (v *Validator) ValidateString(input string) error {
+	return v.compiled.ValidateString(input)
+}
+
+// ValidateDetailed is like ValidateBytes but it returns a flat list of ValidationError.
+func (v *Validator) ValidateDetailed(input []byte) []ValidationError {
+	return v.compiled.ValidateDetailed(input)
+}
+
+// Unmarshal fills the default values of a json object, validates it according to the compiled Schema and then stores
+// it in the value pointed to by dst.
+func (v *Validator) Unmarshal(input []byte, dst interface{}) error {
+	return v.compiled.Unmarshal(input, dst)
+}
+
+// ValidateValue validates a Go value according to the compiled Schema without encoding it to json.
+func (v *Validator) ValidateValue(value interface{}) error {
+	return v.compiled.ValidateValue(value)
+}
+
+// Apply fills the missing fields of a json object with their default values, validates the result according to the
+// compiled Schema and returns it.
+func (v *Validator) Apply(input []byte) ([]byte, error) {
+	return v.compiled.Apply(input)
+}
+
+// ValidateMap validates a decoded json object according to the compiled Schema and returns it.
+func (v *Validator) ValidateMap(values map[string]interface{}, opts ...MapOption) (map[string]interface{}, error) {
+	return v.compiled.ValidateMap(values, opts...)
+}
+
+// ValidateValues validates query parameters or form data according to the compiled Schema with coercion.
+func (v *Validator) ValidateValues(values url.Values) (map[string]interface{}, error) {
+	return v.compiled.ValidateValues(values)
+}
+
+// ValidateYAML receives a YAML document and validates it according to the compiled Schema.
+func (v *Validator) ValidateYAML(input []byte) error {
+	return v.compiled.ValidateYAML(input)
+}
+
+// ValidateTOML receives a TOML document and validates it according to the compiled Schema.
+func (v *Validator) ValidateTOML(input []byte) error {
+	return v.compiled.ValidateTOML(input)
+}
+
+// ValidateStream validates a stream of newline delimited json objects according to the compiled Schema.
+func (v *Validator) ValidateStream(r io.Reader, opts StreamOptions) (*StreamResult, error) {
+	return v.compiled.ValidateStream(r, opts)
+}
@@ -0,0 +1,225 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"regexp"
+	"testing"
+)
+
+func TestSchema_Compile(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		schema := NewSchema(
+			String("name").Required().Format("^[a-z]+$"),
+			Array("tags", String("tag").Format("^#")),
+			Object("address", NewSchema(
+				String("zip").Format("^[0-9]{5}$"),
+			)),
+		)
+
+		validator, err := schema.Compile()
+		assert.Nil(t, err)
+		assert.NotNil(t, validator)
+		assert.Len(t, validator.Schema().Fields, 3)
+
+		err = validator.ValidateString(`{"name": "foo", "tags": ["#a"], "address": {"zip": "12345"}}`)
+		assert.Nil(t, err)
+
+		err = validator.ValidateBytes([]byte(`{"name": "foo", "tags": ["#a"], "address": {"zip": "123"}}`))
+		assert.NotNil(t, err)
+
+		errs := validator.ValidateDetailed([]byte(`{"name": "Foo", "tags": ["a"]}`))
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "/name", errs[0].Path)
+		assert.Equal(t, "/tags/0", errs[1].Path)
+	})
+	t.Run("invalid_format", func(t *testing.T) {
+		schema := NewSchema(
+			String("name").Format("[a-z"),
+		)
+		validator, err := schema.Compile()
+		assert.NotNil(t, err)
+		assert.Nil(t, validator)
+	})
+	t.Run("nested_invalid_format", func(t *testing.T) {
+		schema := NewSchema(
+			Array("items", Object("item", NewSchema(
+				String("code").Format("(?<"),
+			))),
+		)
+		_, err := schema.Compile()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "items")
+		assert.Contains(t, err.Error(), "code")
+	})
+	t.Run("nil_field", func(t *testing.T) {
+		_, err := NewSchema(String("name"), nil).Compile()
+		assert.NotNil(t, err)
+
+		_, err = NewSchema(Array("items", nil)).Compile()
+		assert.NotNil(t, err)
+	})
+	t.Run("parsed_schema", func(t *testing.T) {
+		schema, err := ReadFromString(`{"fields":[{"name":"bar","type": "string","format":"[0-"}]}`)
+		assert.Nil(t, err)
+
+		_, err = schema.Compile()
+		assert.NotNil(t, err)
+	})
+	t.Run("compiled_state", func(t *testing.T) {
+		address := NewSchema(String("city").Required())
+		schema := NewSchema(
+			String("name").MinLength(5),
+			Integer("name").Min(10),
+			Object("address", address),
+		).Strict()
+
+		validator, err := schema.Compile()
+		assert.Nil(t, err)
+		assert.NotNil(t, validator.compiled.compiled)
+		assert.Nil(t, validator.Schema().compiled)
+		assert.Equal(t, []int{0, 1}, validator.compiled.compiled.indexes["name"])
+		nested := validator.compiled.Fields[2].(*ObjectField).schema
+		assert.NotNil(t, nested.compiled)
+
+		// fields with the same name are validated like in the schema
+		assert.NotNil(t, validator.ValidateString(`{"name": 15}`))
+		assert.Equal(t, schema.ValidateDetailed([]byte(`{"name": "John Doe", "other": 1}`)), validator.ValidateDetailed([]byte(`{"name": "John Doe", "other": 1}`)))
+
+		// changing the fields of the schema after Compile does not change the validator
+		schema.Fields[2].(*ObjectField).schema.Fields = append(schema.Fields[2].(*ObjectField).schema.Fields, String("zip").Required())
+		assert.Nil(t, validator.ValidateString(`{"address": {"city": "Paris"}}`))
+		assert.NotNil(t, validator.ValidateString(`{"address": {"city": "Paris", "zip": 1}}`))
+	})
+}
+
+func TestSchema_NestedValues(t *testing.T) {
+	schema := NewSchema(
+		Object("object", NewSchema(
+			Integer("age").Required(),
+		)),
+		Array("items", Integer("item")),
+	)
+
+	err := schema.ValidateString(`{"object": "{\"age\": 1}"}`)
+	assert.NotNil(t, err)
+
+	err = schema.ValidateString(`{"items": {"0": 1}}`)
+	assert.NotNil(t, err)
+
+	err = schema.ValidateString(`{"object": {"age": 1}, "items": [1, 2]}`)
+	assert.Nil(t, err)
+
+	// field names are keys of the object, not gjson paths
+	schema = NewSchema(Integer("a.b").Required())
+	assert.Nil(t, schema.ValidateString(`{"a.b": 1}`))
+	assert.NotNil(t, schema.ValidateString(`{"a": {"b": 1}}`))
+}
+
+func benchmarkSchema() Schema {
+	return NewSchema(
+		String("id").Required().Format("^evt_[a-zA-Z0-9]+$"),
+		String("type").Required().Choices("charge.succeeded", "charge.failed"),
+		Integer("created").Required().Positive(),
+		Object("data", NewSchema(
+			String("currency").Required().Format("^[a-z]{3}$"),
+			Integer("amount").Required().Min(0),
+			Array("items", Object("item", NewSchema(
+				String("sku").Required().Format("^[A-Z0-9-]+$"),
+				Integer("quantity").Required().Range(1, 100),
+				Float("price").Positive(),
+			))).MinLength(1),
+			Object("customer", NewSchema(
+				String("email").Required().Format("^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$"),
+				Boolean("verified"),
+			)).Required(),
+		)).Required(),
+	)
+}
+
+var benchmarkInput = []byte(`{
+	"id": "evt_1A2b3C4d",
+	"type": "charge.succeeded",
+	"created": 1700000000,
+	"data": {
+		"currency": "usd",
+		"amount": 4200,
+		"items": [
+			{"sku": "SKU-1", "quantity": 1, "price": 10.5},
+			{"sku": "SKU-2", "quantity": 2, "price": 5.25},
+			{"sku": "SKU-3", "quantity": 3, "price": 7}
+		],
+		"customer": {"email": "john.doe@example.com", "verified": true}
+	}
+}`)
+
+func BenchmarkSchema_ValidateBytesNested(b *testing.B) {
+	s := benchmarkSchema()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = s.ValidateBytes(benchmarkInput)
+	}
+}
+
+// legacyValidateBytes validates a json like ValidateBytes did before Compile: every field is looked up with a gjson
+// path and converted to a Go value, nested objects are marshalled and parsed again, and regex formats are compiled
+// for every value. it is only used as the baseline of the nested benchmark.
+func legacyValidateBytes(s *Schema, input []byte) error {
+	var result error
+	for _, field := range s.Fields {
+		err := legacyValidate(field, gjson.GetBytes(input, field.GetName()).Value())
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+func legacyValidate(field Field, value interface{}) error {
+	switch f := field.(type) {
+	case *ObjectField:
+		if value == nil {
+			return f.Validate(nil)
+		}
+		content, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return legacyValidateBytes(&f.schema, content)
+	case *ArrayField:
+		items, ok := value.([]interface{})
+		if !ok {
+			return f.Validate(value)
+		}
+		var result error
+		for _, item := range items {
+			err := legacyValidate(f.items, item)
+			if err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		return result
+	case *StringField:
+		if f.validateFormat {
+			if _, err := regexp.Compile(f.format); err != nil {
+				return err
+			}
+		}
+	}
+	return field.Validate(value)
+}
+
+// BenchmarkSchema_ValidateBytesNestedBaseline measures the validation of nested values before Compile, so it could be
+// compared with BenchmarkSchema_ValidateBytesNested.
+func BenchmarkSchema_ValidateBytesNestedBaseline(b *testing.B) {
+	s := benchmarkSchema()
+	if err := legacyValidateBytes(&s, benchmarkInput); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = legacyValidateBytes(&s, benchmarkInput)
+	}
+}
@@ -0,0 +1,390 @@
+package vjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/tidwall/gjson"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidateValue validates a Go value according to the Schema, e.g. a map[string]interface{} which is decoded from
+// msgpack or read from a database, without encoding it to json. maps, slices and pointers are walked directly, and
+// Go numbers like int64, uint and json.Number are accepted for number fields. structs are validated with their json
+// encoding. custom validators receive numbers other than float64 as json.Number. values are validated like
+// ValidateBytes validates their json encoding, so the value of an object field should be a map or a struct.
+func (s *Schema) ValidateValue(v interface{}) error {
+	value, err := normalizeValue(v)
+	if err != nil {
+		return newValidationError("", typeRule, nil, v, "Value could not be validated: %v", err)
+	}
+	return s.validateValue(value)
+}
+
+// validateValue validates a Go value according to the Schema. a value which is not an object is validated as an
+// object without properties, like a json document which is not an object.
+func (s *Schema) validateValue(value interface{}) error {
+	if s.root != nil {
+		var result error
+		err := validateGoValue(s.root, value)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		err = s.validateValueRules(value)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		if result == nil {
+			err = s.validateValueComparisons(value, nil)
+			if err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		return result
+	}
+
+	object, _ := value.(map[string]interface{})
+	var result error
+	var invalid map[string]bool
+	for _, field := range s.Fields {
+		fieldName := field.GetName()
+		var err error
+		if fieldValue, found := object[fieldName]; found {
+			err = validateGoValue(field, fieldValue)
+		} else {
+			err = field.Validate(nil)
+		}
+		if err != nil {
+			result = multierror.Append(result, prefixPath(err, fieldName))
+			if invalid == nil {
+				invalid = make(map[string]bool)
+			}
+			invalid[fieldName] = true
+		}
+	}
+
+	err := s.validateAdditionalValues(object)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	err = s.validateValueRules(value)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	err = s.validateValueComparisons(value, invalid)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	return result
+}
+
+// validateAdditionalValues checks properties of an object which are not declared in schema fields.
+// properties are checked in the order of their names, since maps have no order.
+func (s *Schema) validateAdditionalValues(object map[string]interface{}) error {
+	if !s.checksAdditionalProperties() || len(object) == 0 {
+		return nil
+	}
+	declared := s.declaredNames()
+	names := make([]string, 0, len(object))
+	for name := range object {
+		if _, found := declared[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var result error
+	for _, name := range names {
+		value, err := normalizeValue(object[name])
+		if err != nil {
+			result = multierror.Append(result, conversionError(name, object[name], err))
+			continue
+		}
+		err = s.validateAdditionalProperty(name, value, func(field Field) error {
+			return validateGoValue(field, value)
+		})
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// validateValueRules validates a Go value with conditional rules and dependencies of the schema.
+func (s *Schema) validateValueRules(value interface{}) error {
+	var result error
+	for _, conditional := range s.conditionals {
+		branch := conditional.otherwise
+		if conditional.condition.validateValue(value) == nil {
+			branch = conditional.then
+		}
+		if branch == nil {
+			continue
+		}
+		err := branch.validateValue(value)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	if object, ok := value.(map[string]interface{}); ok && len(s.dependencies) > 0 {
+		present := make(map[string]struct{}, len(object))
+		for name := range object {
+			present[name] = struct{}{}
+		}
+		err := s.validateDependencies(present)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// validateValueComparisons validates a Go value with comparisons of the schema. the values of compared fields are
+// encoded to json, so they are compared like the values of a json object.
+func (s *Schema) validateValueComparisons(value interface{}, invalid map[string]bool) error {
+	object, ok := value.(map[string]interface{})
+	if len(s.comparisons) == 0 || !ok {
+		return nil
+	}
+	values := make(map[string]gjson.Result)
+	for _, comparison := range s.comparisons {
+		for _, operand := range []Operand{comparison.left, comparison.right} {
+			for _, name := range operand.fields {
+				fieldValue, found := object[name]
+				if _, done := values[name]; done || !found {
+					continue
+				}
+				content, err := json.Marshal(fieldValue)
+				if err != nil {
+					continue
+				}
+				values[name] = gjson.ParseBytes(content)
+			}
+		}
+	}
+	return s.compare(values, invalid)
+}
+
+// validateGoValue validates a Go value which is present in an object or an array with the given field.
+// a nil value is validated like a JSON null.
+func validateGoValue(field Field, value interface{}) error {
+	normalized, err := normalizeValue(value)
+	if err != nil {
+		return conversionError(field.GetName(), value, err)
+	}
+	if normalized == nil {
+		return validateNull(field)
+	}
+	return validateField(field, normalized)
+}
+
+// validateField validates a Go value which is not nil with the given field, like Validate. object fields do not parse
+// strings as json here, so nested values are validated like the values of a json document.
+func validateField(field Field, value interface{}) error {
+	if object, ok := field.(*ObjectField); ok {
+		err := object.validateValue(value)
+		if err != nil {
+			return err
+		}
+		return object.validators.validate(object.name, value)
+	}
+	return field.Validate(value)
+}
+
+func conversionError(name string, value interface{}, err error) *ValidationError {
+	return newValidationError(name, typeRule, nil, value, "Value for %s could not be validated: %v", name, err)
+}
+
+// normalizeValue converts a Go value to the types which are used for decoded json, so fields can validate it.
+// maps with string keys are converted to map[string]interface{}, slices and arrays to []interface{}, pointers to
+// their values, numbers other than float64 to json.Number and named types to their underlying types. values of maps
+// and slices are not converted, since they are converted when they are validated. structs are converted with their
+// json encoding.
+func normalizeValue(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case nil, string, bool, float64, json.Number, map[string]interface{}, []interface{}:
+		return v, nil
+	}
+
+	reflected := reflect.ValueOf(v)
+	switch reflected.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if reflected.IsNil() {
+			return nil, nil
+		}
+		return normalizeValue(reflected.Elem().Interface())
+	case reflect.Map:
+		if reflected.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if reflected.IsNil() {
+			return nil, nil
+		}
+		object := make(map[string]interface{}, reflected.Len())
+		iterator := reflected.MapRange()
+		for iterator.Next() {
+			object[iterator.Key().String()] = iterator.Value().Interface()
+		}
+		return object, nil
+	case reflect.Slice, reflect.Array:
+		// byte slices are encoded as base64 strings in json
+		if reflected.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		if reflected.Kind() == reflect.Slice && reflected.IsNil() {
+			return nil, nil
+		}
+		items := make([]interface{}, 0, reflected.Len())
+		for index := 0; index < reflected.Len(); index++ {
+			items = append(items, reflected.Index(index).Interface())
+		}
+		return items, nil
+	case reflect.String:
+		return reflected.String(), nil
+	case reflect.Bool:
+		return reflected.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(reflected.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(reflected.Uint(), 10)), nil
+	case reflect.Float32:
+		return json.Number(strconv.FormatFloat(reflected.Float(), 'g', -1, 32)), nil
+	case reflect.Float64:
+		return reflected.Float(), nil
+	}
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var value interface{}
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// toFloat converts a float64 or a json.Number to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case json.Number:
+		number, err := value.Float64()
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// maxExponent is the max exponent of json numbers in exponent notation which are converted to integers and decimals,
+// so a short number like 1e1000000000 can not allocate a huge integer.
+const maxExponent = 1000
+
+// bigInteger converts a Go number to big.Int exactly. ok is false if v is not a number, and the integer is nil if the
+// number is not an integer.
+func bigInteger(v interface{}) (*big.Int, bool) {
+	if number, ok := toInt64(v); ok {
+		return big.NewInt(number), true
+	}
+	if number, ok := toUint64(v); ok {
+		return new(big.Int).SetUint64(number), true
+	}
+	switch value := v.(type) {
+	case float64:
+		return floatInteger(value), true
+	case float32:
+		return floatInteger(float64(value)), true
+	case json.Number:
+		return parseInteger(string(value))
+	}
+	return nil, false
+}
+
+func floatInteger(value float64) *big.Int {
+	if math.IsNaN(value) || math.IsInf(value, 0) || value != math.Trunc(value) {
+		return nil
+	}
+	number, _ := big.NewFloat(value).Int(nil)
+	return number
+}
+
+// parseInteger parses the text of a json number. numbers like 1.0 and 1e3 are integers too.
+// numbers whose exponents are out of range are not parsed, and they are reported with exponentOutOfRange.
+func parseInteger(text string) (*big.Int, bool) {
+	if number, ok := new(big.Int).SetString(text, 10); ok {
+		return number, true
+	}
+	if exponentOutOfRange(text) {
+		return nil, true
+	}
+	number, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, false
+	}
+	if !number.IsInt() {
+		return nil, true
+	}
+	return number.Num(), true
+}
+
+// exponentOutOfRange returns whether the text of a json number has an exponent which is larger than maxExponent or
+// smaller than -maxExponent.
+func exponentOutOfRange(text string) bool {
+	index := strings.IndexAny(text, "eE")
+	if index < 0 {
+		return false
+	}
+	exponent, err := strconv.Atoi(text[index+1:])
+	if numError, ok := err.(*strconv.NumError); ok {
+		return numError.Err == strconv.ErrRange
+	}
+	return exponent > maxExponent || exponent < -maxExponent
+}
+
+// integerActual returns the actual value of an integer in validation errors, which is an int if it fits in int.
+func integerActual(number *big.Int) interface{} {
+	if number.IsInt64() && number.Int64() >= math.MinInt && number.Int64() <= math.MaxInt {
+		return int(number.Int64())
+	}
+	return number
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch value := v.(type) {
+	case int:
+		return int64(value), true
+	case int8:
+		return int64(value), true
+	case int16:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int64:
+		return value, true
+	}
+	return 0, false
+}
+
+func toUint64(v interface{}) (uint64, bool) {
+	switch value := v.(type) {
+	case uint:
+		return uint64(value), true
+	case uint8:
+		return uint64(value), true
+	case uint16:
+		return uint64(value), true
+	case uint32:
+		return uint64(value), true
+	case uint64:
+		return value, true
+	}
+	return 0, false
+}
@@ -0,0 +1,154 @@
+package vjson
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type valueStatus string
+
+type valueAddress struct {
+	City string `json:"city"`
+}
+
+func TestSchema_ValidateValue(t *testing.T) {
+	schema := NewSchema(
+		Integer("id").Min(1).Required(),
+		Float("score").Max(10),
+		String("status").Choices("active", "inactive"),
+		Array("tags", String("tag")).MaxLength(2),
+		Object("address", NewSchema(String("city").Required())),
+		Integer("manager").Nullable(),
+	)
+
+	t.Run("valid", func(t *testing.T) {
+		city := "Paris"
+		assert.Nil(t, schema.ValidateValue(map[string]interface{}{
+			"id":      int64(42),
+			"score":   json.Number("9.5"),
+			"status":  valueStatus("active"),
+			"tags":    []string{"a", "b"},
+			"address": map[string]*string{"city": &city},
+			"manager": nil,
+		}))
+		assert.Nil(t, schema.ValidateValue(map[string]interface{}{"id": uint(1), "score": float32(1.5), "address": valueAddress{City: "Paris"}}))
+		assert.Nil(t, schema.ValidateValue(map[string]interface{}{"id": json.Number("7"), "score": 3}))
+		assert.Nil(t, schema.ValidateValue(&map[string]interface{}{"id": 1}))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		errs := toValidationErrors(schema.ValidateValue(map[string]interface{}{
+			"id":      int64(0),
+			"score":   uint8(11),
+			"status":  valueStatus("deleted"),
+			"tags":    []string{"a", "b", "c"},
+			"address": map[string]interface{}{"city": 1},
+		}))
+		assert.Len(t, errs, 5)
+		assert.Equal(t, "/id", errs[0].Path)
+		assert.Equal(t, "min", errs[0].Rule)
+		assert.Equal(t, "/score", errs[1].Path)
+		assert.Equal(t, "max", errs[1].Rule)
+		assert.Equal(t, "/status", errs[2].Path)
+		assert.Equal(t, "/tags", errs[3].Path)
+		assert.Equal(t, "/address/city", errs[4].Path)
+
+		errs = toValidationErrors(schema.ValidateValue(map[string]interface{}{"manager": nil, "tags": []interface{}{nil}}))
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "/id", errs[0].Path)
+		assert.Equal(t, "required", errs[0].Rule)
+		assert.Equal(t, "/tags/0", errs[1].Path)
+
+		assert.NotNil(t, schema.ValidateValue(map[string]interface{}{"id": make(chan int)}))
+	})
+	t.Run("rules", func(t *testing.T) {
+		schema := NewSchema(
+			String("method").Required(),
+			Integer("min"),
+			Integer("max"),
+		).Strict().
+			When(If(NewSchema(String("method").Choices("card"))).Then(NewSchema(String("card").Required()))).
+			DependentRequired("min", "max").
+			LessThan("min", "max")
+
+		assert.Nil(t, schema.ValidateValue(map[string]interface{}{"method": "card", "card": "4111", "min": 1, "max": int64(2)}))
+		errs := toValidationErrors(schema.ValidateValue(map[string]interface{}{"method": "card", "min": 3, "extra": true}))
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "/extra", errs[0].Path)
+		assert.Equal(t, "/card", errs[1].Path)
+		assert.Equal(t, "/max", errs[2].Path)
+
+		errs = toValidationErrors(schema.ValidateValue(map[string]interface{}{"method": "cash", "min": uint(3), "max": 2}))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "less_than", errs[0].Rule)
+	})
+	t.Run("root", func(t *testing.T) {
+		schema := NewRootSchema(Array("", Integer("id").Positive()))
+		assert.Nil(t, schema.ValidateValue([]int64{1, 2}))
+		errs := toValidationErrors(schema.ValidateValue([]int{1, -2}))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "/1", errs[0].Path)
+	})
+}
+
+func TestIntegerField_GoNumbers(t *testing.T) {
+	field := Integer("foo").Min(1)
+	for _, value := range []interface{}{int8(5), int16(5), int32(5), int64(5), uint(5), uint8(5), uint16(5), uint32(5), uint64(5), json.Number("5")} {
+		assert.Nil(t, field.Validate(value))
+	}
+	assert.NotNil(t, field.Validate(int64(0)))
+	assert.NotNil(t, field.Validate(json.Number("x")))
+	assert.NotNil(t, field.Validate(uint64(1<<63)))
+}
+
+func TestSchema_ValidateValueParity(t *testing.T) {
+	schema := NewSchema(
+		Integer("id").Required(),
+		Object("address", NewSchema(String("city").Required())),
+		Array("contacts", Object("contact", NewSchema(String("email").Required()))),
+		Ref("billing", "address"),
+		OneOf("payment", Object("card", NewSchema(String("number").Required())), String("iban")),
+	).Define("address", Object("address", NewSchema(String("city").Required())))
+
+	fixtures := []string{
+		`{"id": 1, "address": {"city": "Paris"}, "contacts": [{"email": "a@b.c"}], "billing": {"city": "Paris"}, "payment": "FR76"}`,
+		`{"id": 1, "address": 5}`,
+		`{"id": 1, "address": "{\"city\": \"Paris\"}"}`,
+		`{"id": 1, "address": [{"city": "Paris"}]}`,
+		`{"id": 1, "address": true}`,
+		`{"id": 1, "address": {}}`,
+		`{"id": 1, "contacts": ["{\"email\": \"a@b.c\"}", 1, {}]}`,
+		`{"id": 1, "billing": "{\"city\": \"Paris\"}"}`,
+		`{"id": 1, "payment": {"number": "4111"}}`,
+		`{"id": 1, "payment": 1}`,
+		`{"id": "1"}`,
+		`[]`,
+	}
+	for _, fixture := range fixtures {
+		t.Run(fixture, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(fixture))
+			decoder.UseNumber()
+			var value interface{}
+			assert.Nil(t, decoder.Decode(&value))
+
+			bytesErrors := schema.ValidateDetailed([]byte(fixture))
+			valueErrors := toValidationErrors(schema.ValidateValue(value))
+			assert.Equal(t, len(bytesErrors), len(valueErrors))
+			for index := range bytesErrors {
+				if index >= len(valueErrors) {
+					break
+				}
+				assert.Equal(t, bytesErrors[index].Path, valueErrors[index].Path)
+				assert.Equal(t, bytesErrors[index].Rule, valueErrors[index].Rule)
+			}
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		assert.Nil(t, schema.ValidateValue(map[string]interface{}{"id": 1, "address": valueAddress{City: "Paris"}}))
+		errs := toValidationErrors(schema.ValidateValue(map[string]interface{}{"id": 1, "address": valueStatus("x")}))
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "Value for address should be an object", errs[0].Message)
+	})
+}